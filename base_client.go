@@ -2,21 +2,26 @@ package telegram
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/jfk9w-go/flu/logf"
 
 	"github.com/jfk9w-go/flu"
 	"github.com/jfk9w-go/flu/httpf"
+	"github.com/jfk9w-go/flu/syncf"
 	"github.com/pkg/errors"
 )
 
 // baseClient represents a flu/http.Request factory.
 type baseClient struct {
-	client   httpf.Client
-	endpoint endpointFunc
+	client       httpf.Client
+	endpoint     endpointFunc
+	fileEndpoint endpointFunc
+	maxFileSize  int64
+	local        bool
+	retry        RetryPolicy
+	clock        syncf.Clock
+	invoker      Invoker
 }
 
 // ValidStatusCodes is a slice of valid API HTTP status codes.
@@ -41,49 +46,39 @@ func (c *baseClient) GetUpdates(ctx context.Context, options GetUpdatesOptions)
 	return updates, c.Execute(ctx, "getUpdates", flu.JSON(options), &updates)
 }
 
-// GetMe is a simple method for testing your bot's auth token. Requires no parameters.
-// Returns basic information about the bot in form of a User object.
-// See https://core.telegram.org/bots/api#getme
-func (c *baseClient) GetMe(ctx context.Context) (*User, error) {
-	user := new(User)
-	return user, c.Execute(ctx, "getMe", nil, user)
-}
-
-func (c *baseClient) ForwardMessage(ctx context.Context, chatID ChatID, ref MessageRef, options *SendOptions) (ID, error) {
-	var messageID ID
-	form, err := options.body(chatID, ref)
+// SetWebhook is used to specify a URL and receive incoming updates via an outgoing webhook.
+// Pass an empty URL to remove webhook integration.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#setwebhook
+func (c *baseClient) SetWebhook(ctx context.Context, url string, options *WebhookOptions) error {
+	body, err := options.body(url)
 	if err != nil {
-		return messageID, err
+		return err
 	}
 
-	return messageID, c.Execute(ctx, "forwardMessage", form, &messageID)
-}
-
-func (c *baseClient) CopyMessage(ctx context.Context, chatID ChatID, ref MessageRef, options *CopyOptions) (ID, error) {
-	var resp struct {
-		MessageID ID `json:"message_id"`
+	var ok bool
+	if err := c.Execute(ctx, "setWebhook", body, &ok); err != nil {
+		return err
 	}
 
-	form, err := options.body(chatID, ref)
-	if err != nil {
-		return 0, err
+	if !ok {
+		return errors.New("not ok")
 	}
 
-	return resp.MessageID, c.Execute(ctx, "copyMessage", form, &resp)
+	return nil
 }
 
-// DeleteMessage is used to delete a message, including service messages, with the following limitations:
-// - A message can only be deleted if it was sent less than 48 hours ago.
-// - Bots can delete outgoing messages in private chats, groups, and supergroups.
-// - Bots granted can_post_messages permissions can delete outgoing messages in channels.
-// - If the bot is an administrator of a group, it can delete any message there.
-// - If the bot has can_delete_messages permission in a supergroup or a updateChannel, it can delete any message there.
+// DeleteWebhook is used to remove webhook integration if you decide to switch back to getUpdates.
 // Returns True on success.
-// See
-//    https://core.telegram.org/bots/api#deletemessage
-func (c *baseClient) DeleteMessage(ctx context.Context, ref MessageRef) error {
+// See https://core.telegram.org/bots/api#deletewebhook
+func (c *baseClient) DeleteWebhook(ctx context.Context, dropPendingUpdates bool) error {
+	body := new(httpf.Form)
+	if dropPendingUpdates {
+		body = body.Set("drop_pending_updates", "1")
+	}
+
 	var ok bool
-	if err := c.Execute(ctx, "deleteMessage", ref.form(), &ok); err != nil {
+	if err := c.Execute(ctx, "deleteWebhook", body, &ok); err != nil {
 		return err
 	}
 
@@ -94,31 +89,32 @@ func (c *baseClient) DeleteMessage(ctx context.Context, ref MessageRef) error {
 	return nil
 }
 
-func (c *baseClient) EditMessageReplyMarkup(ctx context.Context, ref MessageRef, markup ReplyMarkup) (*Message, error) {
-	markupJSON, err := json.Marshal(markup)
-	if err != nil {
-		return nil, err
-	}
-
-	form := ref.form().Set("reply_markup", string(markupJSON))
-	message := new(Message)
-	if err := c.Execute(ctx, "editMessageReplyMarkup", form, &message); err != nil {
-		if tgerr := new(Error); errors.As(err, tgerr) &&
-			strings.Contains(tgerr.Description, "message is not modified") {
-			return nil, nil
-		}
-
-		return nil, err
-	}
+// GetWebhookInfo is used to get current webhook status.
+// On success, returns a WebhookInfo object.
+// If the bot is using getUpdates, will return an object with the url field empty.
+// See https://core.telegram.org/bots/api#getwebhookinfo
+func (c *baseClient) GetWebhookInfo(ctx context.Context) (*WebhookInfo, error) {
+	info := new(WebhookInfo)
+	return info, c.Execute(ctx, "getWebhookInfo", nil, info)
+}
 
-	return message, nil
+// GetMe is a simple method for testing your bot's auth token. Requires no parameters.
+// Returns basic information about the bot in form of a User object.
+// See https://core.telegram.org/bots/api#getme
+func (c *baseClient) GetMe(ctx context.Context) (*User, error) {
+	user := new(User)
+	return user, c.Execute(ctx, "getMe", nil, user)
 }
 
-func (c *baseClient) ExportChatInviteLink(ctx context.Context, chatID ChatID) (string, error) {
+// GetFile is used to get basic info about a file and prepare it for downloading.
+// For the moment, bots can download files of up to 20MB in size.
+// On success, a File object is returned.
+// See https://core.telegram.org/bots/api#getfile
+func (c *baseClient) GetFile(ctx context.Context, fileID string) (*File, error) {
 	body := new(httpf.Form).
-		Set("chat_id", chatID.queryParam())
-	var inviteLink string
-	return inviteLink, c.Execute(ctx, "exportChatInviteLink", body, &inviteLink)
+		Set("file_id", fileID)
+	file := new(File)
+	return file, c.Execute(ctx, "getFile", body, file)
 }
 
 // GetChat is used to get up to date information about the chat (current name of
@@ -180,6 +176,34 @@ func (c *baseClient) AnswerCallbackQuery(ctx context.Context, id string, options
 	return nil
 }
 
+// AnswerInlineQuery is used to send answers to an inline query.
+// No more than 50 results per query are allowed.
+// On success, True is returned.
+// See https://core.telegram.org/bots/api#answerinlinequery
+func (c *baseClient) AnswerInlineQuery(ctx context.Context, id string, results []InlineQueryResult, options *AnswerInlineQueryOptions) error {
+	type request struct {
+		InlineQueryID string              `json:"inline_query_id"`
+		Results       []InlineQueryResult `json:"results"`
+		*AnswerInlineQueryOptions
+	}
+
+	if results == nil {
+		results = make([]InlineQueryResult, 0)
+	}
+
+	req := request{id, results, options}
+	var ok bool
+	if err := c.Execute(ctx, "answerInlineQuery", flu.JSON(req), &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
 func (c *baseClient) SetMyCommands(ctx context.Context, scope *BotCommandScope, commands []BotCommand) error {
 	type request struct {
 		Commands []BotCommand     `json:"commands"`
@@ -227,28 +251,36 @@ func (c *baseClient) DeleteMyCommands(ctx context.Context, scope *BotCommandScop
 	return nil
 }
 
-func (c *baseClient) SendChatAction(ctx context.Context, chatID ChatID, action string) error {
-	body := new(httpf.Form).
-		Set("chat_id", chatID.queryParam()).
-		Set("action", action)
-	var ok bool
-	if err := c.Execute(ctx, "sendChatAction", body, &ok); err != nil {
-		return err
-	}
+// Execute makes the API call retrying it according to the retry policy
+// (which may be overridden with WithRetryPolicy).
+func (c *baseClient) Execute(ctx context.Context, method string, body flu.EncoderTo, resp interface{}) error {
+	policy := contextRetryPolicy(ctx, c.retry)
+	for attempt := 1; ; attempt++ {
+		err := c.execute(ctx, method, body, resp)
+		timeout, ok := policy.next(attempt, err)
+		if !ok {
+			return err
+		}
 
-	if !ok {
-		return errors.New("not ok")
+		log().Debugf(ctx, "retry [%s] in %s after attempt %d: %v", method, timeout, attempt, err)
+		if err := flu.Sleep(ctx, timeout); err != nil {
+			return err
+		}
 	}
+}
 
-	return nil
+func (c *baseClient) execute(ctx context.Context, method string, body flu.EncoderTo, resp interface{}) error {
+	return c.invoker(ctx, &Call{Method: method, Body: body, Response: resp})
 }
 
-func (c *baseClient) Execute(ctx context.Context, method string, body flu.EncoderTo, resp interface{}) error {
-	err := httpf.POST(c.endpoint(method), body).
+func (c *baseClient) invoke(ctx context.Context, call *Call) error {
+	start := c.clock.Now()
+	err := httpf.POST(c.endpoint(call.Method), call.Body).
 		Exchange(ctx, c.client).
-		DecodeBody(newResponse(resp)).
+		DecodeBody(newResponse(call.Response)).
 		CheckStatus(ValidStatusCodes...).
 		Error()
-	log().Resultf(ctx, logf.Trace, logf.Warn, "execute [%s]: %v", method, err)
+	call.Duration = c.clock.Now().Sub(start)
+	log().Resultf(ctx, logf.Trace, logf.Warn, "execute [%s]: %v", call.Method, err)
 	return err
 }
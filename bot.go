@@ -25,18 +25,31 @@ type Bot struct {
 	*baseClient
 	*floodControlAware
 	*conversationAware
-	ctx    context.Context
-	cancel context.CancelFunc
-	work   syncf.WaitGroup
-	me     *User
-	once   sync.Once
+	ctx        context.Context
+	cancel     context.CancelFunc
+	work       syncf.WaitGroup
+	me         *User
+	once       sync.Once
+	webhook    *webhook
+	dispatcher dispatcher
+	metrics    metrics
+	clock      syncf.Clock
 }
 
-func NewBot(clock syncf.Clock, client httpf.Client, token string) *Bot {
+func NewBot(clock syncf.Clock, client httpf.Client, token string, options ...BotOption) *Bot {
 	if token == "" {
 		log().Panicf(nil, "token must not be empty")
 	}
 
+	config := &botConfig{endpoint: DefaultEndpoint}
+	for _, option := range options {
+		option(config)
+	}
+
+	if clock == nil {
+		clock = syncf.DefaultClock
+	}
+
 	if client == nil {
 		transport := httpf.NewDefaultTransport()
 		transport.ResponseHeaderTimeout = 2 * time.Minute
@@ -44,13 +57,30 @@ func NewBot(clock syncf.Clock, client httpf.Client, token string) *Bot {
 	}
 
 	baseClient := &baseClient{
-		client:   client,
-		endpoint: func(method string) string { return "https://api.telegram.org/bot" + token + "/" + method },
+		client:       client,
+		endpoint:     config.endpointFunc(token),
+		fileEndpoint: config.fileEndpointFunc(token),
+		maxFileSize:  config.fileSizeLimit(),
+		local:        config.local,
+		retry:        config.retry(),
+		clock:        clock,
 	}
 
+	metrics := metrics{registry: config.metrics}
+	interceptors := config.interceptors
+	if metrics.registry != nil {
+		interceptors = append(interceptors, metrics.interceptor())
+	}
+
+	baseClient.invoker = chain(baseClient.invoke, interceptors)
 	floodControlAware := &floodControlAware{
-		clock:    clock,
-		executor: baseClient,
+		clock:        clock,
+		executor:     baseClient,
+		local:        config.local,
+		chatMigrated: config.chatMigrated,
+		limits:       config.limits(),
+		retry:        config.retry(),
+		metrics:      metrics,
 	}
 
 	conversationAware := &conversationAware{
@@ -64,6 +94,8 @@ func NewBot(clock syncf.Clock, client httpf.Client, token string) *Bot {
 		conversationAware: conversationAware,
 		ctx:               ctx,
 		cancel:            cancel,
+		metrics:           metrics,
+		clock:             clock,
 	}
 }
 
@@ -71,13 +103,42 @@ func (b *Bot) String() string {
 	return rootLoggerName
 }
 
+// AttachMaxSize returns the maximum upload size for the media type.
+// Limits are lifted when WithLocalMode is used.
+func (b *Bot) AttachMaxSize(mediaType MediaType) int64 {
+	if b.floodControlAware.local {
+		return LocalAttachMaxSize
+	}
+
+	return mediaType.AttachMaxSize()
+}
+
 func (b *Bot) Listen(options GetUpdatesOptions) <-chan Update {
+	return b.listen(options, nil)
+}
+
+// listen polls updates using the provided options.
+// If allowedUpdates is not nil, it is called before each poll to get the actual allowed_updates value
+// along with a channel which is closed when the value changes (in which case the poll is restarted).
+func (b *Bot) listen(options GetUpdatesOptions, allowedUpdates func() ([]string, <-chan struct{})) <-chan Update {
 	channel := make(chan Update)
 	_, _ = syncf.GoWith(b.ctx, b.work.Spawn, func(ctx context.Context) {
 		defer close(channel)
 		for {
-			updates, err := b.GetUpdates(ctx, options)
+			pollCtx, cancel := context.WithCancel(ctx)
+			if allowedUpdates != nil {
+				var changed <-chan struct{}
+				options.AllowedUpdates, changed = allowedUpdates()
+				go cancelOn(pollCtx, cancel, changed)
+			}
+
+			updates, err := b.GetUpdates(pollCtx, options)
+			restart := ctx.Err() == nil && pollCtx.Err() != nil
+			cancel()
 			switch {
+			case restart:
+				continue
+
 			case syncf.IsContextRelated(err):
 				return
 
@@ -95,22 +156,8 @@ func (b *Bot) Listen(options GetUpdatesOptions) <-chan Update {
 					}
 
 					options.Offset = update.ID.Increment()
-					if update.Message != nil && update.Message.ReplyToMessage != nil {
-						if err := b.Answer(ctx, update.Message); err == nil {
-							continue
-						} else {
-							log().Warnf(ctx, "answer %d: %s", update.Message.ID, err)
-						}
-					}
-
-					if ctx.Err() != nil {
-						return
-					}
-
-					select {
-					case <-ctx.Done():
+					if err := b.receive(ctx, update, channel); err != nil {
 						return
-					case channel <- update:
 					}
 				}
 			}
@@ -120,6 +167,65 @@ func (b *Bot) Listen(options GetUpdatesOptions) <-chan Update {
 	return channel
 }
 
+// Webhook switches the bot to webhook update delivery.
+// The returned http.Handler should be served at the URL passed to SetWebhook.
+// If secretToken is not empty, requests without matching SecretTokenHeader will be rejected.
+// It must be called before Commands or any listener is registered.
+func (b *Bot) Webhook(secretToken string) http.Handler {
+	if b.webhook != nil {
+		log().Panicf(nil, "webhook is already set")
+	}
+
+	b.webhook = &webhook{
+		bot:         b,
+		secretToken: secretToken,
+		updates:     make(chan Update),
+	}
+
+	return b.webhook
+}
+
+// cancelOn calls cancel when signal is closed unless ctx is done first.
+func cancelOn(ctx context.Context, cancel context.CancelFunc, signal <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+	case <-signal:
+		cancel()
+	}
+}
+
+func (b *Bot) updates(options GetUpdatesOptions, allowedUpdates func() ([]string, <-chan struct{})) <-chan Update {
+	if b.webhook != nil {
+		return b.webhook.updates
+	}
+
+	return b.listen(options, allowedUpdates)
+}
+
+func (b *Bot) receive(ctx context.Context, update Update, channel chan<- Update) error {
+	b.metrics.update(update.kind())
+	if update.Message != nil && update.Message.ReplyToMessage != nil {
+		if err := b.Answer(ctx, update.Message); err == nil {
+			return nil
+		} else {
+			log().Warnf(ctx, "answer %d: %s", update.Message.ID, err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-b.ctx.Done():
+		return b.ctx.Err()
+	case channel <- update:
+		return nil
+	}
+}
+
 func (b *Bot) Username() Username {
 	b.once.Do(func() {
 		ctx, cancel := context.WithTimeout(b.ctx, time.Minute)
@@ -135,19 +241,37 @@ func (b *Bot) Username() Username {
 	return *b.me.Username
 }
 
+// DefaultCommandsOptions are used for polling updates for Commands and listeners.
+// AllowedUpdates are used for Commands only, other listeners add their update types as necessary.
 var DefaultCommandsOptions = &GetUpdatesOptions{
 	TimeoutSecs:    60,
-	AllowedUpdates: []string{"message", "edited_message", "callback_query"},
+	AllowedUpdates: []string{UpdateMessage, UpdateEditedMessage, UpdateCallbackQuery},
 }
 
 func (b *Bot) Commands() <-chan *Command {
-	updates := b.Listen(*DefaultCommandsOptions)
+	updates := b.subscribe(DefaultCommandsOptions.AllowedUpdates...)
 	commands := make(chan *Command)
 	_, _ = syncf.GoWith(b.ctx, b.work.Spawn, func(ctx context.Context) {
 		defer close(commands)
-		for update := range updates {
+		for {
+			var update Update
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-updates:
+				if !ok {
+					return
+				}
+
+				update = value
+			}
+
 			if cmd := b.extractCommand(update); cmd != nil {
-				commands <- cmd
+				select {
+				case <-ctx.Done():
+					return
+				case commands <- cmd:
+				}
 			}
 		}
 	})
@@ -171,13 +295,14 @@ func (b *Bot) CommandListener(value interface{}) *Bot {
 
 	commands := b.Commands()
 	_, _ = syncf.GoWith(b.ctx, b.work.Spawn, func(ctx context.Context) {
+		ctx = WithPriority(ctx, HighPriority)
 		for cmd := range commands {
 			err := b.onStart(ctx, cmd)
 			switch {
 			case syncf.IsContextRelated(err):
 				return
 			case err == nil:
-				err = listener.OnCommand(ctx, b, cmd)
+				err = b.onCommand(ctx, listener, cmd)
 				if syncf.IsContextRelated(err) {
 					return
 				}
@@ -194,6 +319,123 @@ func (b *Bot) CommandListener(value interface{}) *Bot {
 	return b
 }
 
+// InlineQueryListener starts dispatching inline queries to the listener.
+// See https://core.telegram.org/bots/inline
+func (b *Bot) InlineQueryListener(listener InlineQueryListener) *Bot {
+	return b.UpdateListenerFunc(func(ctx context.Context, client Client, update *Update) error {
+		return listener.OnInlineQuery(ctx, client, update.InlineQuery)
+	}, UpdateInlineQuery)
+}
+
+func (b *Bot) InlineQueryListenerFunc(fun InlineQueryListenerFunc) *Bot {
+	return b.InlineQueryListener(fun)
+}
+
+// PaymentListener starts dispatching shipping and pre-checkout queries to the listener.
+func (b *Bot) PaymentListener(listener PaymentListener) *Bot {
+	return b.UpdateListenerFunc(func(ctx context.Context, client Client, update *Update) error {
+		if update.ShippingQuery != nil {
+			return listener.OnShippingQuery(ctx, client, update.ShippingQuery)
+		}
+
+		return listener.OnPreCheckoutQuery(ctx, client, update.PreCheckoutQuery)
+	}, UpdateShippingQuery, UpdatePreCheckoutQuery)
+}
+
+// PollListener starts dispatching poll and poll answer updates to the listener.
+func (b *Bot) PollListener(listener PollListener) *Bot {
+	return b.UpdateListenerFunc(func(ctx context.Context, client Client, update *Update) error {
+		if update.Poll != nil {
+			return listener.OnPoll(ctx, client, update.Poll)
+		}
+
+		return listener.OnPollAnswer(ctx, client, update.PollAnswer)
+	}, UpdatePoll, UpdatePollAnswer)
+}
+
+// ChatMemberListener starts dispatching chat member updates to the listener.
+// Both my_chat_member and chat_member updates are requested.
+// Note that the bot must be an administrator in the chat to receive chat_member updates.
+func (b *Bot) ChatMemberListener(listener ChatMemberListener) *Bot {
+	return b.UpdateListenerFunc(func(ctx context.Context, client Client, update *Update) error {
+		member := update.ChatMember
+		if member == nil {
+			member = update.MyChatMember
+		}
+
+		return listener.OnMemberEvent(ctx, client, member.Event(), member)
+	}, UpdateMyChatMember, UpdateChatMember)
+}
+
+// JoinRequestListener starts dispatching chat join requests to the listener.
+func (b *Bot) JoinRequestListener(listener JoinRequestListener) *Bot {
+	return b.UpdateListenerFunc(func(ctx context.Context, client Client, update *Update) error {
+		return listener.OnJoinRequest(ctx, client, update.ChatJoinRequest)
+	}, UpdateChatJoinRequest)
+}
+
+// UpdateListener starts dispatching updates of the provided types to the handler (for example, a Router).
+// All update types are dispatched if none are provided.
+// It works with both polling and Webhook update sources.
+// Other listeners (except CommandListener) are built on top of it.
+// Handlers run with HighPriority.
+// Handler errors (including timeouts of requests made by the handler) are logged, and the listener
+// keeps running until the Bot is closed.
+func (b *Bot) UpdateListener(handler UpdateHandler, updateTypes ...string) *Bot {
+	if len(updateTypes) == 0 {
+		updateTypes = AllUpdates
+	}
+
+	updates := b.subscribe(updateTypes...)
+	_, _ = syncf.GoWith(b.ctx, b.work.Spawn, func(ctx context.Context) {
+		ctx = WithPriority(ctx, HighPriority)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case update := <-updates:
+				err := handler.OnUpdate(ctx, b, &update)
+				if ctx.Err() != nil {
+					return
+				}
+
+				logf.Get(b).Resultf(ctx, logf.Debug, logf.Error, "handle %s update %d: %v", update.kind(), update.ID, err)
+			}
+		}
+	})
+
+	return b
+}
+
+func (b *Bot) UpdateListenerFunc(fun UpdateHandlerFunc, updateTypes ...string) *Bot {
+	return b.UpdateListener(fun, updateTypes...)
+}
+
+// DialogListener starts dispatching message and callback query updates to the dialogs
+// and handling dialog step timeouts (checked every Dialogs.ExpireInterval according to the dialogs clock).
+func (b *Bot) DialogListener(dialogs *Dialogs) *Bot {
+	b.UpdateListener(dialogs, UpdateMessage, UpdateEditedMessage, UpdateCallbackQuery)
+	_, _ = syncf.GoWith(b.ctx, b.work.Spawn, func(ctx context.Context) {
+		ctx = WithPriority(ctx, HighPriority)
+		ticker := time.NewTicker(dialogs.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := dialogs.Expire(ctx, b); ctx.Err() != nil {
+					return
+				} else if err != nil {
+					logf.Get(b).Errorf(ctx, "expire dialogs: %v", err)
+				}
+			}
+		}
+	})
+
+	return b
+}
+
 func (b *Bot) onStart(ctx context.Context, cmd *Command) error {
 	if cmd.Key == "/start" && cmd.Payload != "" {
 		var payload string
@@ -216,7 +458,7 @@ func (b *Bot) CommandListenerFunc(fun CommandListenerFunc) *Bot {
 }
 
 func (b *Bot) HandleCommand(ctx context.Context, listener CommandListener, cmd *Command) error {
-	err := listener.OnCommand(ctx, b, cmd)
+	err := b.onCommand(ctx, listener, cmd)
 	if syncf.IsContextRelated(err) {
 		return err
 	}
@@ -232,6 +474,13 @@ func (b *Bot) HandleCommand(ctx context.Context, listener CommandListener, cmd *
 	return nil
 }
 
+func (b *Bot) onCommand(ctx context.Context, listener CommandListener, cmd *Command) error {
+	start := b.clock.Now()
+	err := listener.OnCommand(ctx, b, cmd)
+	b.metrics.command(cmd.Key, b.clock.Now().Sub(start), err)
+	return err
+}
+
 func (b *Bot) extractCommand(update Update) *Command {
 	switch {
 	case update.Message != nil:
@@ -246,15 +495,16 @@ func (b *Bot) extractCommand(update Update) *Command {
 }
 
 func (b *Bot) extractCommandMessage(message *Message) *Command {
-	for _, entity := range message.Entities {
-		if entity.Type == "bot_command" {
+	text, entities := message.entitySource()
+	for _, entity := range entities {
+		if entity.Type == BotCommandEntity {
 			cmd := &Command{
 				User:    &message.From,
 				Chat:    &message.Chat,
 				Message: message,
 			}
 
-			cmd.init(b.Username(), message.Text[entity.Offset:])
+			cmd.init(b.Username(), newUTF16Text(text).slice(entity.Offset, -1))
 			return cmd
 		}
 	}
@@ -0,0 +1,165 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jfk9w-go/flu/me3x"
+)
+
+const (
+	// DefaultEndpoint is the Telegram Bot API server URL used by default.
+	DefaultEndpoint = "https://api.telegram.org"
+	// LocalAttachMaxSize is the maximum upload size when working with a local Bot API server.
+	LocalAttachMaxSize int64 = 2000 << 20
+	// DefaultMaxFileSize is the maximum download size for getFile.
+	DefaultMaxFileSize int64 = 20 << 20
+)
+
+type botConfig struct {
+	endpoint     string
+	test         bool
+	local        bool
+	maxFileSize  int64
+	chatMigrated ChatMigrationFunc
+	rateLimits   *RateLimits
+	retryPolicy  *RetryPolicy
+	interceptors []Interceptor
+	metrics      me3x.Registry
+}
+
+// BotOption is used to configure a Bot in NewBot.
+type BotOption func(config *botConfig)
+
+// WithEndpoint sets the Bot API server URL.
+// This is useful for self-hosted Bot API servers.
+func WithEndpoint(endpoint string) BotOption {
+	return func(config *botConfig) {
+		config.endpoint = strings.TrimRight(endpoint, "/")
+	}
+}
+
+// WithTestEnvironment makes the Bot use the test environment.
+// See https://core.telegram.org/bots/webapps#using-bots-in-the-test-environment
+func WithTestEnvironment() BotOption {
+	return func(config *botConfig) {
+		config.test = true
+	}
+}
+
+// WithLocalMode should be used with a Bot API server running with --local flag.
+// Upload size limits returned by Bot.AttachMaxSize are lifted, and flu.File inputs are sent as file:// URIs instead of being uploaded.
+// See https://github.com/tdlib/telegram-bot-api#usage
+func WithLocalMode() BotOption {
+	return func(config *botConfig) {
+		config.local = true
+	}
+}
+
+// WithMaxFileSize sets the maximum size of files downloaded with OpenFile and DownloadFile.
+// DefaultMaxFileSize is used by default (unless WithLocalMode is set).
+// Negative values lift the limit.
+func WithMaxFileSize(size int64) BotOption {
+	return func(config *botConfig) {
+		config.maxFileSize = size
+	}
+}
+
+// ChatMigrationFunc is called when a group chat is found to be migrated to a supergroup.
+type ChatMigrationFunc func(ctx context.Context, from ChatID, to ID)
+
+// WithChatMigration makes the Bot automatically retry sending to a group which was migrated to a supergroup
+// using the new chat ID. The callback is called before retrying (so that stored chat IDs may be updated).
+// Without this option ChatMigrated error is returned.
+func WithChatMigration(callback ChatMigrationFunc) BotOption {
+	return func(config *botConfig) {
+		if callback == nil {
+			callback = func(context.Context, ChatID, ID) {}
+		}
+
+		config.chatMigrated = callback
+	}
+}
+
+// WithRateLimits sets flood control limits.
+// DefaultRateLimits are used by default.
+func WithRateLimits(limits RateLimits) BotOption {
+	return func(config *botConfig) {
+		config.rateLimits = &limits
+	}
+}
+
+// WithDefaultRetryPolicy sets the retry policy applied to all API calls.
+// DefaultRetryPolicy is used by default.
+// It may be overridden for a single call with WithRetryPolicy.
+func WithDefaultRetryPolicy(policy RetryPolicy) BotOption {
+	return func(config *botConfig) {
+		config.retryPolicy = &policy
+	}
+}
+
+// WithInterceptors adds interceptors which are called around every Bot API call.
+// Interceptors are called in the order they are added, the first one being the outermost.
+func WithInterceptors(interceptors ...Interceptor) BotOption {
+	return func(config *botConfig) {
+		config.interceptors = append(config.interceptors, interceptors...)
+	}
+}
+
+// WithMetrics enables reporting Bot metrics to the registry:
+// API call counts and latencies, flood control wait times, received updates and command handler durations.
+// Metric names are prefixed with "telegram".
+func WithMetrics(registry me3x.Registry) BotOption {
+	return func(config *botConfig) {
+		config.metrics = registry.WithPrefix("telegram")
+	}
+}
+
+func (c *botConfig) retry() RetryPolicy {
+	if c.retryPolicy != nil {
+		return *c.retryPolicy
+	}
+
+	return DefaultRetryPolicy()
+}
+
+func (c *botConfig) limits() RateLimits {
+	if c.rateLimits != nil {
+		return *c.rateLimits
+	}
+
+	return DefaultRateLimits()
+}
+
+func (c *botConfig) fileSizeLimit() int64 {
+	switch {
+	case c.maxFileSize != 0:
+		return c.maxFileSize
+	case c.local:
+		return -1
+	default:
+		return DefaultMaxFileSize
+	}
+}
+
+func (c *botConfig) fileEndpointFunc(token string) endpointFunc {
+	prefix := c.endpoint + "/file/bot" + token
+	if c.test {
+		prefix += "/test"
+	}
+
+	return func(path string) string {
+		return prefix + "/" + path
+	}
+}
+
+func (c *botConfig) endpointFunc(token string) endpointFunc {
+	prefix := c.endpoint + "/bot" + token
+	if c.test {
+		prefix += "/test"
+	}
+
+	return func(method string) string {
+		return prefix + "/" + method
+	}
+}
@@ -0,0 +1,29 @@
+package telegram_test
+
+import (
+	"context"
+	"testing"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBot_NilClock(t *testing.T) {
+	f := telegramtest.Setup(t, nil)
+	ctx := timeout(t)
+	_, err := f.Bot.GetMe(ctx)
+	assert.Nil(t, err)
+
+	_, err = f.Bot.Send(ctx, f.Chat.ID, telegram.Text{Text: "hello"}, nil)
+	assert.Nil(t, err)
+
+	handled := make(chan bool, 1)
+	f.Bot.CommandListenerFunc(func(ctx context.Context, client telegram.Client, cmd *telegram.Command) error {
+		handled <- true
+		return nil
+	})
+
+	f.Server.SendMessage(f.User.ID, f.Chat.ID, "/ping")
+	receive(t, handled, "command")
+}
@@ -1,20 +1,47 @@
 package telegram
 
-import "context"
+import (
+	"context"
+
+	"github.com/jfk9w-go/flu"
+)
 
 type Client interface {
 	GetMe(ctx context.Context) (*User, error)
 	ForwardMessage(ctx context.Context, chatID ChatID, ref MessageRef, options *SendOptions) (ID, error)
 	CopyMessage(ctx context.Context, chatID ChatID, ref MessageRef, options *CopyOptions) (ID, error)
 	DeleteMessage(ctx context.Context, ref MessageRef) error
-	EditMessageReplyMarkup(ctx context.Context, ref MessageRef, markup ReplyMarkup) (*Message, error)
+	EditMessageReplyMarkup(ctx context.Context, target EditTarget, markup ReplyMarkup) (*Message, error)
+	EditMessageText(ctx context.Context, target EditTarget, text Text, markup ReplyMarkup) (*Message, error)
+	EditMessageCaption(ctx context.Context, target EditTarget, caption Text, markup ReplyMarkup) (*Message, error)
+	EditMessageMedia(ctx context.Context, target EditTarget, media Media, markup ReplyMarkup) (*Message, error)
+	GetFile(ctx context.Context, fileID string) (*File, error)
+	OpenFile(ctx context.Context, fileID string) (flu.Input, error)
+	DownloadFile(ctx context.Context, fileID string, out flu.Output) (int64, error)
 	ExportChatInviteLink(ctx context.Context, chatID ChatID) (string, error)
+	CreateChatInviteLink(ctx context.Context, chatID ChatID, options *InviteLinkOptions) (*ChatInviteLink, error)
+	EditChatInviteLink(ctx context.Context, chatID ChatID, inviteLink string, options *InviteLinkOptions) (*ChatInviteLink, error)
+	RevokeChatInviteLink(ctx context.Context, chatID ChatID, inviteLink string) (*ChatInviteLink, error)
+	ApproveChatJoinRequest(ctx context.Context, chatID ChatID, userID ID) error
+	DeclineChatJoinRequest(ctx context.Context, chatID ChatID, userID ID) error
 	GetChat(ctx context.Context, chatID ChatID) (*Chat, error)
 	GetChatAdministrators(ctx context.Context, chatID ChatID) ([]ChatMember, error)
 	GetChatMemberCount(ctx context.Context, chatID ChatID) (int64, error)
 	GetChatMember(ctx context.Context, chatID ChatID, userID ID) (*ChatMember, error)
+	BanChatMember(ctx context.Context, chatID ChatID, userID ID, options *BanOptions) error
+	UnbanChatMember(ctx context.Context, chatID ChatID, userID ID, onlyIfBanned bool) error
+	RestrictChatMember(ctx context.Context, chatID ChatID, userID ID, permissions ChatPermissions, options *RestrictOptions) error
+	PromoteChatMember(ctx context.Context, chatID ChatID, userID ID, rights ChatAdministratorRights) error
+	SetChatPermissions(ctx context.Context, chatID ChatID, permissions ChatPermissions, useIndependentChatPermissions bool) error
+	SetChatAdministratorCustomTitle(ctx context.Context, chatID ChatID, userID ID, customTitle string) error
 	AnswerCallbackQuery(ctx context.Context, id string, options *AnswerOptions) error
+	AnswerInlineQuery(ctx context.Context, id string, results []InlineQueryResult, options *AnswerInlineQueryOptions) error
+	CreateInvoiceLink(ctx context.Context, invoice Invoice) (string, error)
+	AnswerShippingQuery(ctx context.Context, id string, options []ShippingOption, errorMessage string) error
+	AnswerPreCheckoutQuery(ctx context.Context, id string, errorMessage string) error
+	RefundStarPayment(ctx context.Context, userID ID, telegramPaymentChargeID string) error
 	Send(ctx context.Context, chatID ChatID, item Sendable, options *SendOptions) (*Message, error)
+	StopPoll(ctx context.Context, ref MessageRef, markup ReplyMarkup) (*Poll, error)
 	SendChatAction(ctx context.Context, chatID ChatID, action string) error
 	SendMediaGroup(ctx context.Context, chatID ChatID, media []Media, options *SendOptions) ([]Message, error)
 	SetMyCommands(ctx context.Context, scope *BotCommandScope, commands []BotCommand) error
@@ -0,0 +1,23 @@
+package telegram_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBot_CommandListener(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	replies := make(chan string, 1)
+	f.Bot.CommandListener(telegram.CommandListenerFunc(func(ctx context.Context, client telegram.Client, cmd *telegram.Command) error {
+		replies <- cmd.Key + " " + cmd.Payload
+		return nil
+	}))
+
+	f.Server.SendMessage(f.User.ID, f.Chat.ID, "/echo hello world")
+	assert.Equal(t, "/echo hello world", receive(t, replies, "command"))
+}
@@ -0,0 +1,340 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jfk9w-go/flu/syncf"
+	"github.com/pkg/errors"
+)
+
+// DialogEnd is returned from dialog step handlers in order to finish the dialog.
+const DialogEnd = ""
+
+// DefaultDialogExpireInterval is the default interval between dialog timeout checks in Bot.DialogListener.
+const DefaultDialogExpireInterval = time.Second
+
+// DialogKey identifies a dialog with a user in a chat.
+type DialogKey struct {
+	ChatID ID `json:"chat_id"`
+	UserID ID `json:"user_id"`
+}
+
+// DialogState is the persisted state of a dialog.
+type DialogState struct {
+	// Step is the current step name.
+	Step string `json:"step"`
+	// Data contains values collected during the dialog.
+	Data map[string]string `json:"data,omitempty"`
+	// Deadline is the time when the current step times out.
+	// Zero value means no timeout.
+	Deadline time.Time `json:"deadline"`
+}
+
+// Dialog is passed to dialog step handlers.
+type Dialog struct {
+	DialogKey
+	DialogState
+}
+
+// Get returns the dialog value for the key.
+func (d *Dialog) Get(key string) string {
+	return d.Data[key]
+}
+
+// Set sets the dialog value for the key.
+// Values are saved to DialogStorage along with the dialog state.
+func (d *Dialog) Set(key, value string) {
+	if d.Data == nil {
+		d.Data = make(map[string]string)
+	}
+
+	d.Data[key] = value
+}
+
+// DialogStep describes a single dialog state.
+type DialogStep struct {
+	// Enter is called when the dialog enters the step (for example, to ask a question).
+	// It is optional.
+	Enter func(ctx context.Context, client Client, dialog *Dialog) error
+	// Handle is called on message and callback query updates from the dialog user in the dialog chat.
+	// It returns the next step name (the current one in order to stay, DialogEnd in order to finish the dialog).
+	Handle func(ctx context.Context, client Client, dialog *Dialog, update *Update) (string, error)
+	// Timeout is the maximum time to wait for an update in this step.
+	// Zero value means no timeout.
+	Timeout time.Duration
+	// OnTimeout is called when the step times out. It returns the next step name.
+	// The dialog is finished on timeout if OnTimeout is nil.
+	OnTimeout func(ctx context.Context, client Client, dialog *Dialog) (string, error)
+}
+
+// Dialogs is a finite-state conversation manager.
+// Every (chat, user) pair may have at most one active dialog which is driven by incoming updates.
+// Dialog states are kept in DialogStorage so that dialogs may survive restarts.
+// Dialogs may be used with Bot.DialogListener or as an UpdateHandler in a Router (along with Active filter).
+// In the latter case Expire should be called periodically in order to handle step timeouts.
+// Updates of the same dialog are handled sequentially, while different dialogs are handled concurrently.
+type Dialogs struct {
+	clock    syncf.Clock
+	storage  DialogStorage
+	interval time.Duration
+	steps    map[string]DialogStep
+	locks    map[DialogKey]*dialogLock
+	mu       sync.Mutex
+}
+
+// NewDialogs creates a Dialogs instance.
+// syncf.DefaultClock is used if clock is nil.
+// MemoryDialogStorage is used if storage is nil.
+func NewDialogs(clock syncf.Clock, storage DialogStorage) *Dialogs {
+	if clock == nil {
+		clock = syncf.DefaultClock
+	}
+
+	if storage == nil {
+		storage = new(MemoryDialogStorage)
+	}
+
+	return &Dialogs{
+		clock:    clock,
+		storage:  storage,
+		interval: DefaultDialogExpireInterval,
+		steps:    make(map[string]DialogStep),
+		locks:    make(map[DialogKey]*dialogLock),
+	}
+}
+
+// ExpireInterval sets the interval between dialog timeout checks in Bot.DialogListener.
+// DefaultDialogExpireInterval is used by default.
+func (d *Dialogs) ExpireInterval(interval time.Duration) *Dialogs {
+	if interval <= 0 {
+		log().Panicf(nil, "dialog expire interval must be positive")
+	}
+
+	d.interval = interval
+	return d
+}
+
+// Step adds a dialog step.
+func (d *Dialogs) Step(name string, step DialogStep) *Dialogs {
+	if name == DialogEnd {
+		log().Panicf(nil, "dialog step name must not be empty")
+	}
+
+	if step.Handle == nil {
+		log().Panicf(nil, "dialog step %s must have Handle set", name)
+	}
+
+	d.steps[name] = step
+	return d
+}
+
+// Start starts a new dialog (replacing the active one, if any) at the provided step.
+func (d *Dialogs) Start(ctx context.Context, client Client, key DialogKey, step string) error {
+	defer d.lock(key)()
+	dialog := &Dialog{DialogKey: key}
+	return d.transition(ctx, client, dialog, step)
+}
+
+// Cancel finishes the active dialog, if any.
+func (d *Dialogs) Cancel(ctx context.Context, key DialogKey) error {
+	defer d.lock(key)()
+	return d.storage.Delete(ctx, key)
+}
+
+// Get returns the active dialog state.
+// nil is returned if there is no active dialog.
+func (d *Dialogs) Get(ctx context.Context, key DialogKey) (*DialogState, error) {
+	return d.storage.Load(ctx, key)
+}
+
+// Active returns a Filter matching updates which belong to an active dialog.
+func (d *Dialogs) Active() Filter {
+	return func(client Client, update *Update) bool {
+		key, ok := dialogKey(update)
+		if !ok {
+			return false
+		}
+
+		state, err := d.storage.Load(context.Background(), key)
+		return err == nil && state != nil
+	}
+}
+
+// OnUpdate passes the update to the current step of the active dialog.
+// Updates which do not belong to an active dialog are ignored.
+// If the current step has already timed out, the timeout is handled instead.
+func (d *Dialogs) OnUpdate(ctx context.Context, client Client, update *Update) error {
+	key, ok := dialogKey(update)
+	if !ok {
+		return nil
+	}
+
+	defer d.lock(key)()
+	state, err := d.storage.Load(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "load dialog")
+	}
+
+	if state == nil {
+		return nil
+	}
+
+	dialog := &Dialog{DialogKey: key, DialogState: *state}
+	step, ok := d.steps[state.Step]
+	if !ok {
+		return d.unknownStep(ctx, key, state.Step)
+	}
+
+	if d.expired(state) {
+		return d.timeout(ctx, client, dialog, step)
+	}
+
+	next, err := step.Handle(ctx, client, dialog, update)
+	if err != nil {
+		return err
+	}
+
+	return d.transition(ctx, client, dialog, next)
+}
+
+// Expire handles timeouts of all expired dialogs.
+func (d *Dialogs) Expire(ctx context.Context, client Client) error {
+	keys, err := d.storage.Expired(ctx, d.clock.Now())
+	if err != nil {
+		return errors.Wrap(err, "list expired dialogs")
+	}
+
+	for _, key := range keys {
+		if err := d.expire(ctx, client, key); syncf.IsContextRelated(err) {
+			return err
+		} else if err != nil {
+			log().Warnf(ctx, "dialog timeout for %d @ %d: %v", key.UserID, key.ChatID, err)
+		}
+	}
+
+	return nil
+}
+
+// expire handles the dialog timeout if the dialog is still expired.
+func (d *Dialogs) expire(ctx context.Context, client Client, key DialogKey) error {
+	defer d.lock(key)()
+	state, err := d.storage.Load(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "load dialog")
+	}
+
+	if state == nil || !d.expired(state) {
+		return nil
+	}
+
+	step, ok := d.steps[state.Step]
+	if !ok {
+		return d.unknownStep(ctx, key, state.Step)
+	}
+
+	dialog := &Dialog{DialogKey: key, DialogState: *state}
+	return d.timeout(ctx, client, dialog, step)
+}
+
+func (d *Dialogs) expired(state *DialogState) bool {
+	return !state.Deadline.IsZero() && !d.clock.Now().Before(state.Deadline)
+}
+
+func (d *Dialogs) timeout(ctx context.Context, client Client, dialog *Dialog, step DialogStep) error {
+	next := DialogEnd
+	if step.OnTimeout != nil {
+		var err error
+		if next, err = step.OnTimeout(ctx, client, dialog); err != nil {
+			_ = d.storage.Delete(ctx, dialog.DialogKey)
+			return err
+		}
+	}
+
+	return d.transition(ctx, client, dialog, next)
+}
+
+// transition moves the dialog to the next step and saves its state.
+// Enter is called only if the step changes. The step deadline is reset on every transition.
+func (d *Dialogs) transition(ctx context.Context, client Client, dialog *Dialog, next string) error {
+	if next == DialogEnd {
+		return d.storage.Delete(ctx, dialog.DialogKey)
+	}
+
+	step, ok := d.steps[next]
+	if !ok {
+		return d.unknownStep(ctx, dialog.DialogKey, next)
+	}
+
+	if next != dialog.Step {
+		dialog.Step = next
+		if step.Enter != nil {
+			if err := step.Enter(ctx, client, dialog); err != nil {
+				_ = d.storage.Delete(ctx, dialog.DialogKey)
+				return errors.Wrapf(err, "enter dialog step %s", next)
+			}
+		}
+	}
+
+	dialog.Deadline = time.Time{}
+	if step.Timeout > 0 {
+		dialog.Deadline = d.clock.Now().Add(step.Timeout)
+	}
+
+	return d.storage.Save(ctx, dialog.DialogKey, dialog.DialogState)
+}
+
+// lock locks the dialog and returns the function which unlocks it.
+// Only the lock map is guarded by d.mu, so step callbacks of different dialogs do not block each other.
+func (d *Dialogs) lock(key DialogKey) func() {
+	d.mu.Lock()
+	if d.locks == nil {
+		d.locks = make(map[DialogKey]*dialogLock)
+	}
+
+	lock, ok := d.locks[key]
+	if !ok {
+		lock = new(dialogLock)
+		d.locks[key] = lock
+	}
+
+	lock.refs++
+	d.mu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if lock.refs--; lock.refs == 0 {
+			delete(d.locks, key)
+		}
+	}
+}
+
+func (d *Dialogs) unknownStep(ctx context.Context, key DialogKey, step string) error {
+	_ = d.storage.Delete(ctx, key)
+	return errors.Errorf("unknown dialog step %s", step)
+}
+
+type dialogLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// dialogKey returns the dialog key for message and callback query updates.
+func dialogKey(update *Update) (DialogKey, bool) {
+	switch {
+	case update.Message != nil, update.EditedMessage != nil, update.CallbackQuery != nil:
+	default:
+		return DialogKey{}, false
+	}
+
+	chat, user := updateChat(update), updateUser(update)
+	if chat == nil || user == nil {
+		return DialogKey{}, false
+	}
+
+	return DialogKey{ChatID: chat.ID, UserID: user.ID}, true
+}
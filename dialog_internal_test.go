@@ -0,0 +1,184 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testClock struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestDialogs_Expire(t *testing.T) {
+	ctx := context.Background()
+	clock := newTestClock()
+	key := DialogKey{ChatID: 1, UserID: 2}
+
+	var timeouts int
+	dialogs := NewDialogs(clock, nil).
+		Step("ask", DialogStep{
+			Handle: func(ctx context.Context, client Client, dialog *Dialog, update *Update) (string, error) {
+				return DialogEnd, nil
+			},
+			Timeout: time.Minute,
+			OnTimeout: func(ctx context.Context, client Client, dialog *Dialog) (string, error) {
+				timeouts++
+				return "remind", nil
+			},
+		}).
+		Step("remind", DialogStep{
+			Handle: func(ctx context.Context, client Client, dialog *Dialog, update *Update) (string, error) {
+				return DialogEnd, nil
+			},
+			Timeout: time.Minute,
+		})
+
+	assert.NoError(t, dialogs.Start(ctx, nil, key, "ask"))
+	state, err := dialogs.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "ask", state.Step)
+	assert.Equal(t, clock.Now().Add(time.Minute), state.Deadline)
+
+	clock.Add(time.Minute - time.Second)
+	assert.NoError(t, dialogs.Expire(ctx, nil))
+	assert.Equal(t, 0, timeouts)
+
+	clock.Add(time.Second)
+	assert.NoError(t, dialogs.Expire(ctx, nil))
+	assert.Equal(t, 1, timeouts)
+	state, err = dialogs.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "remind", state.Step)
+	assert.Equal(t, clock.Now().Add(time.Minute), state.Deadline)
+
+	// OnTimeout is not set for "remind", so the dialog is finished.
+	clock.Add(time.Minute)
+	assert.NoError(t, dialogs.Expire(ctx, nil))
+	state, err = dialogs.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestDialogs_OnUpdateTimeout(t *testing.T) {
+	ctx := context.Background()
+	clock := newTestClock()
+	update := &Update{Message: &Message{Chat: Chat{ID: 1}, From: User{ID: 2}}}
+
+	var handled, timeouts int
+	dialogs := NewDialogs(clock, nil).
+		Step("ask", DialogStep{
+			Handle: func(ctx context.Context, client Client, dialog *Dialog, update *Update) (string, error) {
+				handled++
+				return "ask", nil
+			},
+			Timeout: time.Minute,
+			OnTimeout: func(ctx context.Context, client Client, dialog *Dialog) (string, error) {
+				timeouts++
+				return DialogEnd, nil
+			},
+		})
+
+	key := DialogKey{ChatID: 1, UserID: 2}
+	assert.NoError(t, dialogs.Start(ctx, nil, key, "ask"))
+	assert.NoError(t, dialogs.OnUpdate(ctx, nil, update))
+	assert.Equal(t, 1, handled)
+
+	clock.Add(time.Minute)
+	assert.NoError(t, dialogs.OnUpdate(ctx, nil, update))
+	assert.Equal(t, 1, handled)
+	assert.Equal(t, 1, timeouts)
+	state, err := dialogs.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestDialogs_ConcurrentKeys(t *testing.T) {
+	ctx := context.Background()
+	blocked, release := make(chan struct{}), make(chan struct{})
+	dialogs := NewDialogs(nil, nil).
+		Step("block", DialogStep{
+			Enter: func(ctx context.Context, client Client, dialog *Dialog) error {
+				close(blocked)
+				<-release
+				return nil
+			},
+			Handle: func(ctx context.Context, client Client, dialog *Dialog, update *Update) (string, error) {
+				return DialogEnd, nil
+			},
+		}).
+		Step("pass", DialogStep{
+			Handle: func(ctx context.Context, client Client, dialog *Dialog, update *Update) (string, error) {
+				return DialogEnd, nil
+			},
+		})
+
+	done := make(chan error)
+	go func() { done <- dialogs.Start(ctx, nil, DialogKey{ChatID: 1, UserID: 1}, "block") }()
+	<-blocked
+
+	// Another dialog must not wait for the blocked step callback.
+	assert.NoError(t, dialogs.Start(ctx, nil, DialogKey{ChatID: 1, UserID: 2}, "pass"))
+	close(release)
+	assert.NoError(t, <-done)
+	assert.Empty(t, dialogs.locks)
+}
+
+func TestMemoryDialogStorage(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := new(MemoryDialogStorage)
+	key := DialogKey{ChatID: 1, UserID: 2}
+
+	state, err := storage.Load(ctx, key)
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+
+	saved := DialogState{Step: "ask", Data: map[string]string{"name": "test"}, Deadline: now}
+	assert.NoError(t, storage.Save(ctx, key, saved))
+	assert.NoError(t, storage.Save(ctx, DialogKey{ChatID: 1, UserID: 3}, DialogState{Step: "ask"}))
+
+	// Saved and loaded states must not share data with the storage.
+	saved.Data["name"] = "changed"
+	state, err = storage.Load(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", state.Data["name"])
+	state.Data["name"] = "changed"
+	state, _ = storage.Load(ctx, key)
+	assert.Equal(t, "test", state.Data["name"])
+
+	keys, err := storage.Expired(ctx, now.Add(-time.Second))
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	keys, err = storage.Expired(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []DialogKey{key}, keys)
+
+	assert.NoError(t, storage.Delete(ctx, key))
+	state, err = storage.Load(ctx, key)
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+	keys, _ = storage.Expired(ctx, now)
+	assert.Empty(t, keys)
+}
@@ -0,0 +1,189 @@
+package telegram
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/pkg/errors"
+)
+
+// DialogStorage stores dialog states.
+type DialogStorage interface {
+	// Load returns the dialog state or nil if there is no active dialog.
+	Load(ctx context.Context, key DialogKey) (*DialogState, error)
+	// Save saves the dialog state.
+	Save(ctx context.Context, key DialogKey, state DialogState) error
+	// Delete removes the dialog state.
+	Delete(ctx context.Context, key DialogKey) error
+	// Expired returns keys of dialogs with deadlines before or at now.
+	Expired(ctx context.Context, now time.Time) ([]DialogKey, error)
+}
+
+// MemoryDialogStorage is a DialogStorage which keeps dialog states in memory.
+// Dialogs do not survive restarts with this storage.
+type MemoryDialogStorage struct {
+	states map[DialogKey]DialogState
+	mu     sync.RWMutex
+}
+
+func (s *MemoryDialogStorage) Load(ctx context.Context, key DialogKey) (*DialogState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if state, ok := s.states[key]; ok {
+		state = copyDialogState(state)
+		return &state, nil
+	}
+
+	return nil, nil
+}
+
+func (s *MemoryDialogStorage) Save(ctx context.Context, key DialogKey, state DialogState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = make(map[DialogKey]DialogState)
+	}
+
+	s.states[key] = copyDialogState(state)
+	return nil
+}
+
+func (s *MemoryDialogStorage) Delete(ctx context.Context, key DialogKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, key)
+	return nil
+}
+
+func (s *MemoryDialogStorage) Expired(ctx context.Context, now time.Time) ([]DialogKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]DialogKey, 0)
+	for key, state := range s.states {
+		if !state.Deadline.IsZero() && !now.Before(state.Deadline) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// FileDialogStorage is a DialogStorage which keeps dialog states in a JSON file.
+// States are cached in memory, and the file is rewritten on every change.
+type FileDialogStorage struct {
+	// Path is the file path.
+	Path   string
+	memory MemoryDialogStorage
+	once   sync.Once
+	err    error
+	mu     sync.Mutex
+}
+
+type fileDialogEntry struct {
+	Key   DialogKey   `json:"key"`
+	State DialogState `json:"state"`
+}
+
+func (s *FileDialogStorage) Load(ctx context.Context, key DialogKey) (*DialogState, error) {
+	if err := s.init(); err != nil {
+		return nil, err
+	}
+
+	return s.memory.Load(ctx, key)
+}
+
+func (s *FileDialogStorage) Save(ctx context.Context, key DialogKey, state DialogState) error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.memory.Save(ctx, key, state)
+	return s.flush()
+}
+
+func (s *FileDialogStorage) Delete(ctx context.Context, key DialogKey) error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, _ := s.memory.Load(ctx, key); state == nil {
+		return nil
+	}
+
+	_ = s.memory.Delete(ctx, key)
+	return s.flush()
+}
+
+func (s *FileDialogStorage) Expired(ctx context.Context, now time.Time) ([]DialogKey, error) {
+	if err := s.init(); err != nil {
+		return nil, err
+	}
+
+	return s.memory.Expired(ctx, now)
+}
+
+func (s *FileDialogStorage) init() error {
+	s.once.Do(func() {
+		file := flu.File(s.Path)
+		if ok, err := file.Exists(); err != nil {
+			s.err = errors.Wrap(err, "check dialog storage file")
+			return
+		} else if !ok {
+			return
+		}
+
+		var entries []fileDialogEntry
+		if err := flu.DecodeFrom(file, flu.JSON(&entries)); err != nil {
+			s.err = errors.Wrap(err, "read dialog storage file")
+			return
+		}
+
+		for _, entry := range entries {
+			_ = s.memory.Save(context.Background(), entry.Key, entry.State)
+		}
+	})
+
+	return s.err
+}
+
+// flush writes all states to a temporary file and renames it so that the file is never left half-written.
+func (s *FileDialogStorage) flush() error {
+	s.memory.mu.RLock()
+	entries := make([]fileDialogEntry, 0, len(s.memory.states))
+	for key, state := range s.memory.states {
+		entries = append(entries, fileDialogEntry{key, state})
+	}
+
+	s.memory.mu.RUnlock()
+
+	temp := flu.File(s.Path + ".tmp")
+	if err := flu.EncodeTo(flu.JSON(entries), temp); err != nil {
+		return errors.Wrap(err, "write dialog storage file")
+	}
+
+	if err := os.Rename(temp.String(), s.Path); err != nil {
+		return errors.Wrap(err, "replace dialog storage file")
+	}
+
+	return nil
+}
+
+func copyDialogState(state DialogState) DialogState {
+	if state.Data != nil {
+		data := make(map[string]string, len(state.Data))
+		for key, value := range state.Data {
+			data[key] = value
+		}
+
+		state.Data = data
+	}
+
+	return state
+}
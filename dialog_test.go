@@ -0,0 +1,118 @@
+package telegram_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialogs_FileStorage(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	server, bot, user, chat := f.Server, f.Bot, f.User, f.Chat
+	key := telegram.DialogKey{ChatID: chat.ID, UserID: user.ID}
+	storage := &telegram.FileDialogStorage{Path: filepath.Join(t.TempDir(), "dialogs.json")}
+
+	newDialogs := func() *telegram.Dialogs {
+		ask := func(text string) func(context.Context, telegram.Client, *telegram.Dialog) error {
+			return func(ctx context.Context, client telegram.Client, dialog *telegram.Dialog) error {
+				_, err := client.Send(ctx, dialog.ChatID, telegram.Text{Text: text}, nil)
+				return err
+			}
+		}
+
+		return telegram.NewDialogs(syncf.DefaultClock, storage).
+			Step("name", telegram.DialogStep{
+				Enter: ask("name?"),
+				Handle: func(ctx context.Context, client telegram.Client, dialog *telegram.Dialog, update *telegram.Update) (string, error) {
+					dialog.Set("name", update.Message.Text)
+					return "age", nil
+				},
+			}).
+			Step("age", telegram.DialogStep{
+				Enter: ask("age?"),
+				Handle: func(ctx context.Context, client telegram.Client, dialog *telegram.Dialog, update *telegram.Update) (string, error) {
+					_, err := client.Send(ctx, dialog.ChatID, telegram.Text{Text: dialog.Get("name") + " " + update.Message.Text}, nil)
+					return telegram.DialogEnd, err
+				},
+				Timeout: time.Hour,
+			})
+	}
+
+	ctx := timeout(t)
+	dialogs := newDialogs()
+	assert.Nil(t, dialogs.Start(ctx, bot, key, "name"))
+	assert.Nil(t, dialogs.OnUpdate(ctx, bot, &telegram.Update{Message: server.SendMessage(user.ID, chat.ID, "Alice")}))
+
+	// simulate restart
+	storage = &telegram.FileDialogStorage{Path: storage.Path}
+	dialogs = newDialogs()
+	state, err := dialogs.Get(ctx, key)
+	assert.Nil(t, err)
+	if assert.NotNil(t, state) {
+		assert.Equal(t, "age", state.Step)
+		assert.False(t, state.Deadline.IsZero())
+	}
+
+	assert.Nil(t, dialogs.OnUpdate(ctx, bot, &telegram.Update{Message: server.SendMessage(user.ID, chat.ID, "42")}))
+	state, err = dialogs.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Nil(t, state)
+
+	var texts []string
+	for _, call := range server.Calls("sendMessage") {
+		texts = append(texts, call.Param("text"))
+	}
+
+	assert.Equal(t, []string{"name?", "age?", "Alice 42"}, texts)
+}
+
+func TestBot_DialogListenerTimeout(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	key := telegram.DialogKey{ChatID: f.Chat.ID, UserID: f.User.ID}
+
+	var (
+		now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		mu  sync.Mutex
+	)
+
+	clock := syncf.ClockFunc(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	})
+
+	timeouts := make(chan string, 1)
+	dialogs := telegram.NewDialogs(clock, nil).
+		ExpireInterval(10*time.Millisecond).
+		Step("ask", telegram.DialogStep{
+			Handle: func(ctx context.Context, client telegram.Client, dialog *telegram.Dialog, update *telegram.Update) (string, error) {
+				return telegram.DialogEnd, nil
+			},
+			Timeout: time.Hour,
+			OnTimeout: func(ctx context.Context, client telegram.Client, dialog *telegram.Dialog) (string, error) {
+				timeouts <- dialog.Step
+				return telegram.DialogEnd, nil
+			},
+		})
+
+	f.Bot.DialogListener(dialogs)
+	ctx := timeout(t)
+	assert.Nil(t, dialogs.Start(ctx, f.Bot, key, "ask"))
+
+	mu.Lock()
+	now = now.Add(time.Hour)
+	mu.Unlock()
+
+	assert.Equal(t, "ask", receive(t, timeouts, "timeout"))
+	assert.Eventually(t, func() bool {
+		state, err := dialogs.Get(ctx, key)
+		return err == nil && state == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
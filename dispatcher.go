@@ -0,0 +1,159 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jfk9w-go/flu/syncf"
+)
+
+// subscriber receives updates of selected types.
+// Updates are queued and delivered by a separate goroutine so that a slow subscriber does not block the others.
+type subscriber struct {
+	updateTypes map[string]bool
+	channel     chan Update
+	queue       []Update
+	signal      chan struct{}
+	mu          sync.Mutex
+}
+
+func (s *subscriber) push(update Update) {
+	s.mu.Lock()
+	s.queue = append(s.queue, update)
+	s.mu.Unlock()
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber) pop() (Update, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return Update{}, false
+	}
+
+	update := s.queue[0]
+	s.queue[0] = Update{}
+	s.queue = s.queue[1:]
+	return update, true
+}
+
+func (s *subscriber) deliver(ctx context.Context) {
+	for {
+		update, ok := s.pop()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.signal:
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case s.channel <- update:
+		}
+	}
+}
+
+// dispatcher fans out updates from a single source (polling or webhook) to multiple subscribers.
+type dispatcher struct {
+	subscribers    []*subscriber
+	allowedUpdates []string
+	// changed is closed when allowedUpdates change so that the current long poll may be restarted.
+	changed chan struct{}
+	once    sync.Once
+	mu      sync.RWMutex
+}
+
+// subscribe returns a channel receiving updates of the provided types.
+// All subscriptions share a single update source which is started on first call.
+// If the subscription extends the set of allowed updates, the current long poll is restarted.
+func (b *Bot) subscribe(updateTypes ...string) <-chan Update {
+	d := &b.dispatcher
+	s := &subscriber{
+		updateTypes: make(map[string]bool, len(updateTypes)),
+		channel:     make(chan Update),
+		signal:      make(chan struct{}, 1),
+	}
+
+	d.mu.Lock()
+	changed := false
+	for _, updateType := range updateTypes {
+		if !d.isAllowed(updateType) {
+			d.allowedUpdates = append(d.allowedUpdates, updateType)
+			changed = true
+		}
+
+		s.updateTypes[updateType] = true
+	}
+
+	if changed && d.changed != nil {
+		close(d.changed)
+		d.changed = nil
+	}
+
+	d.subscribers = append(d.subscribers, s)
+	d.mu.Unlock()
+
+	_, _ = syncf.GoWith(b.ctx, b.work.Spawn, s.deliver)
+	d.once.Do(func() {
+		updates := b.updates(*DefaultCommandsOptions, d.getAllowedUpdates)
+		_, _ = syncf.GoWith(b.ctx, b.work.Spawn, func(ctx context.Context) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case update, ok := <-updates:
+					if !ok {
+						return
+					}
+
+					d.dispatch(update)
+				}
+			}
+		})
+	})
+
+	return s.channel
+}
+
+func (d *dispatcher) dispatch(update Update) {
+	updateType := update.kind()
+	for _, s := range d.getSubscribers() {
+		if s.updateTypes[updateType] {
+			s.push(update)
+		}
+	}
+}
+
+func (d *dispatcher) isAllowed(updateType string) bool {
+	for _, allowed := range d.allowedUpdates {
+		if allowed == updateType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getAllowedUpdates returns the current allowed updates and a channel which is closed when they change.
+func (d *dispatcher) getAllowedUpdates() ([]string, <-chan struct{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.changed == nil {
+		d.changed = make(chan struct{})
+	}
+
+	return append([]string(nil), d.allowedUpdates...), d.changed
+}
+
+func (d *dispatcher) getSubscribers() []*subscriber {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.subscribers
+}
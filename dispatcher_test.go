@@ -0,0 +1,40 @@
+package telegram_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBot_SlowListener(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	group := telegram.Chat{ID: -2, Type: telegram.GroupChat, Title: "Group"}
+	f.Server.AddChat(group)
+
+	blocked := make(chan bool, 1)
+	release := make(chan bool)
+	defer close(release)
+	f.Bot.UpdateListenerFunc(func(ctx context.Context, client telegram.Client, update *telegram.Update) error {
+		blocked <- true
+		<-release
+		return nil
+	}, telegram.UpdateMessage)
+
+	f.Server.SendMessage(f.User.ID, f.Chat.ID, "block")
+	receive(t, blocked, "message")
+
+	// The blocked message listener must not stall other listeners,
+	// and the long poll must be restarted with the new allowed_updates.
+	requests := make(chan telegram.ID, 1)
+	f.Bot.JoinRequestListener(telegram.JoinRequestListenerFunc(func(ctx context.Context, client telegram.Client, request *telegram.ChatJoinRequest) error {
+		requests <- request.From.ID
+		return nil
+	}))
+
+	f.Server.AddUpdate(telegram.Update{ChatJoinRequest: &telegram.ChatJoinRequest{Chat: group, From: f.User}})
+	assert.Equal(t, f.User.ID, receive(t, requests, "join request"))
+}
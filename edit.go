@@ -0,0 +1,163 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/jfk9w-go/flu/httpf"
+	"github.com/pkg/errors"
+)
+
+// EditTarget is either a MessageRef or an InlineMessageID.
+type EditTarget interface {
+	editForm(form *httpf.Form) *httpf.Form
+	chat() ChatID
+}
+
+// InlineMessageID is an identifier of a message sent via the bot in inline mode.
+type InlineMessageID string
+
+func (id InlineMessageID) editForm(form *httpf.Form) *httpf.Form {
+	return form.Set("inline_message_id", string(id))
+}
+
+func (id InlineMessageID) chat() ChatID {
+	return nil
+}
+
+func (r MessageRef) editForm(form *httpf.Form) *httpf.Form {
+	return form.
+		Set("chat_id", r.ChatID.queryParam()).
+		Set("message_id", r.ID.queryParam())
+}
+
+func (r MessageRef) chat() ChatID {
+	return r.ChatID
+}
+
+// editResult is either an edited Message or True for inline messages.
+type editResult struct {
+	message *Message
+}
+
+func (r *editResult) UnmarshalJSON(data []byte) error {
+	if string(data) == "true" {
+		return nil
+	}
+
+	r.message = new(Message)
+	return json.Unmarshal(data, r.message)
+}
+
+func isNotModified(err error) bool {
+	var tgerr Error
+	return errors.As(err, &tgerr) && strings.Contains(tgerr.Description, "message is not modified")
+}
+
+func setReplyMarkup(form *httpf.Form, markup ReplyMarkup) (*httpf.Form, error) {
+	if markup == nil {
+		return form, nil
+	}
+
+	bytes, err := json.Marshal(markup)
+	if err != nil {
+		return nil, errors.Wrap(err, "serialize reply_markup")
+	}
+
+	return form.Set("reply_markup", string(bytes)), nil
+}
+
+// EditMessageText is used to edit text and game messages.
+// On success, if the edited message is not an inline message, the edited Message is returned,
+// otherwise nil is returned. nil is also returned if the message is not modified.
+// See https://core.telegram.org/bots/api#editmessagetext
+func (c *floodControlAware) EditMessageText(ctx context.Context, target EditTarget, text Text, markup ReplyMarkup) (*Message, error) {
+	form, err := setReplyMarkup(target.editForm(httpf.FormValue(text)), markup)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.edit(ctx, "editMessageText", target, form)
+}
+
+// EditMessageCaption is used to edit captions of messages.
+// Text.DisableWebPagePreview is ignored.
+// On success, if the edited message is not an inline message, the edited Message is returned,
+// otherwise nil is returned. nil is also returned if the message is not modified.
+// See https://core.telegram.org/bots/api#editmessagecaption
+func (c *floodControlAware) EditMessageCaption(ctx context.Context, target EditTarget, caption Text, markup ReplyMarkup) (*Message, error) {
+	form := new(httpf.Form).Set("caption", caption.Text)
+	if caption.ParseMode != None {
+		form = form.Set("parse_mode", string(caption.ParseMode))
+	}
+
+	form, err := setReplyMarkup(target.editForm(form), markup)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.edit(ctx, "editMessageCaption", target, form)
+}
+
+// EditMessageMedia is used to edit animation, audio, document, photo, or video messages.
+// On success, if the edited message is not an inline message, the edited Message is returned,
+// otherwise nil is returned. nil is also returned if the message is not modified.
+// See https://core.telegram.org/bots/api#editmessagemedia
+func (c *floodControlAware) EditMessageMedia(ctx context.Context, target EditTarget, media Media, markup ReplyMarkup) (*Message, error) {
+	if c.local {
+		media = media.localize()
+	}
+
+	form, err := setReplyMarkup(target.editForm(new(httpf.Form)), markup)
+	if err != nil {
+		return nil, err
+	}
+
+	var multipart *httpf.MultipartForm
+	bytes, err := json.Marshal(media.inputMedia(&multipart, form, "media0"))
+	if err != nil {
+		return nil, errors.Wrap(err, "serialize media")
+	}
+
+	form = form.Set("media", string(bytes))
+	var body flu.EncoderTo = form
+	if multipart != nil {
+		body = multipart
+	}
+
+	return c.edit(ctx, "editMessageMedia", target, body)
+}
+
+// EditMessageReplyMarkup is used to edit only the reply markup of messages.
+// On success, if the edited message is not an inline message, the edited Message is returned.
+// nil is returned if the message is not modified.
+// See https://core.telegram.org/bots/api#editmessagereplymarkup
+func (c *floodControlAware) EditMessageReplyMarkup(ctx context.Context, target EditTarget, markup ReplyMarkup) (*Message, error) {
+	form, err := setReplyMarkup(target.editForm(new(httpf.Form)), markup)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.edit(ctx, "editMessageReplyMarkup", target, form)
+}
+
+func (c *floodControlAware) edit(ctx context.Context, method string, target EditTarget, body flu.EncoderTo) (*Message, error) {
+	var result editResult
+	err := c.execute(ctx, target.chat(), method, body, &result)
+	switch {
+	case err == errUnknownRecipient:
+		if result.message != nil {
+			c.createLimiter(&result.message.Chat)
+		}
+
+		return result.message, nil
+	case isNotModified(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		return result.message, nil
+	}
+}
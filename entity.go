@@ -0,0 +1,177 @@
+package telegram
+
+import "unicode/utf16"
+
+// EntityType is the type of MessageEntity.
+type EntityType string
+
+const (
+	MentionEntity              EntityType = "mention"
+	HashtagEntity              EntityType = "hashtag"
+	CashtagEntity              EntityType = "cashtag"
+	BotCommandEntity           EntityType = "bot_command"
+	URLEntity                  EntityType = "url"
+	EmailEntity                EntityType = "email"
+	PhoneNumberEntity          EntityType = "phone_number"
+	BoldEntity                 EntityType = "bold"
+	ItalicEntity               EntityType = "italic"
+	UnderlineEntity            EntityType = "underline"
+	StrikethroughEntity        EntityType = "strikethrough"
+	SpoilerEntity              EntityType = "spoiler"
+	BlockquoteEntity           EntityType = "blockquote"
+	ExpandableBlockquoteEntity EntityType = "expandable_blockquote"
+	CodeEntity                 EntityType = "code"
+	PreEntity                  EntityType = "pre"
+	TextLinkEntity             EntityType = "text_link"
+	TextMentionEntity          EntityType = "text_mention"
+	CustomEmojiEntity          EntityType = "custom_emoji"
+)
+
+type (
+	// MessageEntity (https://core.telegram.org/bots/api#messageentity)
+	// Note that Offset and Length are measured in UTF-16 code units.
+	MessageEntity struct {
+		Type          EntityType `json:"type"`
+		Offset        int        `json:"offset"`
+		Length        int        `json:"length"`
+		URL           string     `json:"url,omitempty"`
+		User          *User      `json:"user,omitempty"`
+		Language      string     `json:"language,omitempty"`
+		CustomEmojiID string     `json:"custom_emoji_id,omitempty"`
+	}
+
+	// EntityValue is a MessageEntity along with the text it covers.
+	EntityValue struct {
+		MessageEntity
+		Value string
+	}
+)
+
+// utf16Text is a text representation which allows slicing by UTF-16 code units.
+type utf16Text []uint16
+
+func newUTF16Text(text string) utf16Text {
+	return utf16.Encode([]rune(text))
+}
+
+// slice returns text in range [offset, offset+length) clamped to text bounds.
+// Negative length means up to the end of the text.
+func (t utf16Text) slice(offset, length int) string {
+	if offset < 0 {
+		offset = 0
+	}
+
+	if offset > len(t) {
+		offset = len(t)
+	}
+
+	end := offset + length
+	if length < 0 || end > len(t) {
+		end = len(t)
+	}
+
+	return string(utf16.Decode(t[offset:end]))
+}
+
+// Value returns the part of text covered by the entity.
+// text must be the text (or caption) the entity belongs to.
+func (e MessageEntity) Value(text string) string {
+	return newUTF16Text(text).slice(e.Offset, e.Length)
+}
+
+// entitySource returns message text and its entities, falling back to caption and caption entities.
+func (m *Message) entitySource() (string, []MessageEntity) {
+	if m.Text != "" {
+		return m.Text, m.Entities
+	}
+
+	return m.Caption, m.CaptionEntities
+}
+
+// EntityText returns the part of message text (or caption) covered by the entity.
+func (m *Message) EntityText(entity MessageEntity) string {
+	text, _ := m.entitySource()
+	return entity.Value(text)
+}
+
+// EntityValues returns text (or caption) entities along with their values.
+// If types are specified, only entities of the specified types are returned.
+func (m *Message) EntityValues(types ...EntityType) []EntityValue {
+	text, entities := m.entitySource()
+	if len(entities) == 0 {
+		return nil
+	}
+
+	var (
+		units  = newUTF16Text(text)
+		values = make([]EntityValue, 0, len(entities))
+	)
+
+	for _, entity := range entities {
+		if !entityTypeIn(entity.Type, types) {
+			continue
+		}
+
+		values = append(values, EntityValue{
+			MessageEntity: entity,
+			Value:         units.slice(entity.Offset, entity.Length),
+		})
+	}
+
+	return values
+}
+
+// Mentions returns @username mentions from message text (or caption).
+func (m *Message) Mentions() []string {
+	return m.entityStrings(MentionEntity)
+}
+
+// Hashtags returns #hashtags from message text (or caption).
+func (m *Message) Hashtags() []string {
+	return m.entityStrings(HashtagEntity)
+}
+
+// URLs returns URLs from message text (or caption).
+// Note that this does not include text links (see TextLinks).
+func (m *Message) URLs() []string {
+	return m.entityStrings(URLEntity)
+}
+
+// BotCommands returns /commands from message text (or caption).
+func (m *Message) BotCommands() []string {
+	return m.entityStrings(BotCommandEntity)
+}
+
+// TextLinks returns text links from message text (or caption).
+// Link URL is available in EntityValue.URL.
+func (m *Message) TextLinks() []EntityValue {
+	return m.EntityValues(TextLinkEntity)
+}
+
+func (m *Message) entityStrings(entityType EntityType) []string {
+	values := m.EntityValues(entityType)
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = value.Value
+	}
+
+	return result
+}
+
+func entityTypeIn(entityType EntityType, types []EntityType) bool {
+	if len(types) == 0 {
+		return true
+	}
+
+	for _, t := range types {
+		if t == entityType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageEntity_Value(t *testing.T) {
+	text := "Привет 👋 @user #tag"
+	assert.Equal(t, "@user", MessageEntity{Type: MentionEntity, Offset: 10, Length: 5}.Value(text))
+	assert.Equal(t, "#tag", MessageEntity{Type: HashtagEntity, Offset: 16, Length: 4}.Value(text))
+	assert.Equal(t, "👋", MessageEntity{Type: BoldEntity, Offset: 7, Length: 2}.Value(text))
+
+	// Out of bounds entities are clamped.
+	assert.Equal(t, "#tag", MessageEntity{Type: HashtagEntity, Offset: 16, Length: 10}.Value(text))
+	assert.Equal(t, "", MessageEntity{Type: HashtagEntity, Offset: 30, Length: 4}.Value(text))
+}
+
+func TestMessage_EntityValues(t *testing.T) {
+	message := &Message{
+		Text: "😀 привет @user, see https://example.com",
+		Entities: []MessageEntity{
+			{Type: MentionEntity, Offset: 10, Length: 5},
+			{Type: URLEntity, Offset: 21, Length: 19},
+		},
+	}
+
+	assert.Equal(t, []EntityValue{
+		{MessageEntity: message.Entities[0], Value: "@user"},
+		{MessageEntity: message.Entities[1], Value: "https://example.com"},
+	}, message.EntityValues())
+	assert.Equal(t, []string{"@user"}, message.Mentions())
+	assert.Equal(t, []string{"https://example.com"}, message.URLs())
+	assert.Nil(t, message.Hashtags())
+}
+
+func TestMessage_CaptionEntities(t *testing.T) {
+	message := &Message{
+		Caption: "🎉 фото #tag",
+		CaptionEntities: []MessageEntity{
+			{Type: HashtagEntity, Offset: 8, Length: 4},
+		},
+	}
+
+	assert.Equal(t, []string{"#tag"}, message.Hashtags())
+	assert.Equal(t, "#tag", message.EntityText(message.CaptionEntities[0]))
+}
+
+func TestBot_ExtractCommand(t *testing.T) {
+	username := Username("test_bot")
+	bot := &Bot{me: &User{Username: &username}}
+	bot.once.Do(func() {})
+
+	cmd := bot.extractCommand(Update{Message: &Message{
+		Text: "😀 привет /start@test_bot один two",
+		Entities: []MessageEntity{
+			{Type: BotCommandEntity, Offset: 10, Length: 15},
+		},
+	}})
+
+	if assert.NotNil(t, cmd) {
+		assert.Equal(t, "/start", cmd.Key)
+		assert.Equal(t, "один two", cmd.Payload)
+		assert.Equal(t, []string{"один", "two"}, cmd.Args)
+	}
+
+	cmd = bot.extractCommand(Update{Message: &Message{
+		Caption: "🎉 фото /save",
+		CaptionEntities: []MessageEntity{
+			{Type: BotCommandEntity, Offset: 8, Length: 5},
+		},
+	}})
+
+	if assert.NotNil(t, cmd) {
+		assert.Equal(t, "/save", cmd.Key)
+		assert.Empty(t, cmd.Args)
+	}
+
+	cmd = bot.extractCommand(Update{Message: &Message{Text: "/start", Entities: []MessageEntity{
+		{Type: MentionEntity, Offset: 0, Length: 6},
+	}}})
+
+	assert.Nil(t, cmd)
+}
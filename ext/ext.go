@@ -5,6 +5,7 @@ import (
 
 	"github.com/jfk9w-go/telegram-bot-api"
 	"github.com/jfk9w-go/telegram-bot-api/ext/html"
+	"github.com/jfk9w-go/telegram-bot-api/ext/markdown"
 	"github.com/jfk9w-go/telegram-bot-api/ext/output"
 	"github.com/jfk9w-go/telegram-bot-api/ext/receiver"
 )
@@ -20,3 +21,15 @@ func HTML(ctx context.Context, sender telegram.Sender, chatID telegram.ID) *html
 		},
 	}).WithContext(output.With(ctx, telegram.MaxMessageSize, 0))
 }
+
+func Markdown(ctx context.Context, sender telegram.Sender, chatID telegram.ID) *markdown.Writer {
+	return (&markdown.Writer{
+		Out: &output.Paged{
+			Receiver: &receiver.Chat{
+				Sender:    sender,
+				ID:        chatID,
+				ParseMode: telegram.MarkdownV2,
+			},
+		},
+	}).WithContext(output.With(ctx, telegram.MaxMessageSize, 0))
+}
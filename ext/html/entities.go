@@ -0,0 +1,141 @@
+package html
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf16"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"golang.org/x/net/html"
+)
+
+// Message writes message text (or caption) with formatting applied by its entities.
+func (w *Writer) Message(message *telegram.Message) *Writer {
+	if message.Text != "" {
+		return w.Entities(message.Text, message.Entities)
+	}
+
+	return w.Entities(message.Caption, message.CaptionEntities)
+}
+
+// Entities writes text with formatting applied by entities.
+// Entity offsets and lengths are measured in UTF-16 code units as in Bot API.
+// Entities which are rendered automatically by Telegram clients (mentions, hashtags, URLs, etc.) are written as plain text.
+func (w *Writer) Entities(text string, entities []telegram.MessageEntity) *Writer {
+	if w.err != nil || w.Out.IsOverflown() {
+		return w
+	}
+
+	units := utf16.Encode([]rune(text))
+	entities = append([]telegram.MessageEntity(nil), entities...)
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].Offset != entities[j].Offset {
+			return entities[i].Offset < entities[j].Offset
+		}
+
+		return entities[i].Length > entities[j].Length
+	})
+
+	var (
+		// ends contains end offsets of open entities.
+		ends   []int
+		cursor int
+	)
+
+	writeUpTo := func(offset int) {
+		if offset > len(units) {
+			offset = len(units)
+		}
+
+		if offset > cursor {
+			w.Text(string(utf16.Decode(units[cursor:offset])))
+			cursor = offset
+		}
+	}
+
+	closeUpTo := func(offset int) {
+		for len(ends) > 0 && ends[len(ends)-1] <= offset {
+			writeUpTo(ends[len(ends)-1])
+			w.EndTag()
+			ends = ends[:len(ends)-1]
+		}
+	}
+
+	for _, entity := range entities {
+		if entity.Length <= 0 || entity.Offset < cursor {
+			continue
+		}
+
+		closeUpTo(entity.Offset)
+		writeUpTo(entity.Offset)
+
+		end := entity.Offset + entity.Length
+		if len(ends) > 0 && end > ends[len(ends)-1] {
+			// entities must not overlap partially
+			end = ends[len(ends)-1]
+		}
+
+		if !w.startEntity(entity) {
+			continue
+		}
+
+		ends = append(ends, end)
+	}
+
+	closeUpTo(len(units))
+	writeUpTo(len(units))
+	for range ends {
+		w.EndTag()
+	}
+
+	return w
+}
+
+// startEntity opens the tag for the entity.
+// Returns false if the entity should be written as plain text.
+func (w *Writer) startEntity(entity telegram.MessageEntity) bool {
+	switch entity.Type {
+	case telegram.BoldEntity:
+		w.StartTag("b", nil)
+	case telegram.ItalicEntity:
+		w.StartTag("i", nil)
+	case telegram.UnderlineEntity:
+		w.StartTag("u", nil)
+	case telegram.StrikethroughEntity:
+		w.StartTag("s", nil)
+	case telegram.SpoilerEntity:
+		w.StartTag("tg-spoiler", nil)
+	case telegram.CodeEntity:
+		w.StartTag("code", nil)
+	case telegram.PreEntity:
+		if entity.Language != "" {
+			w.openTag(Tag{
+				Open:  fmt.Sprintf(`<pre><code class="language-%s">`, html.EscapeString(entity.Language)),
+				Close: "</code></pre>",
+			})
+		} else {
+			w.StartTag("pre", nil)
+		}
+	case telegram.BlockquoteEntity:
+		w.StartTag("blockquote", nil)
+	case telegram.ExpandableBlockquoteEntity:
+		w.openTag(Tag{Open: "<blockquote expandable>", Close: "</blockquote>"})
+	case telegram.CustomEmojiEntity:
+		w.openTag(Tag{
+			Open:  fmt.Sprintf(`<tg-emoji emoji-id="%s">`, html.EscapeString(entity.CustomEmojiID)),
+			Close: "</tg-emoji>",
+		})
+	case telegram.TextLinkEntity:
+		w.StartTag("a", []html.Attribute{{Key: "href", Val: entity.URL}})
+	case telegram.TextMentionEntity:
+		if entity.User == nil {
+			return false
+		}
+
+		w.StartTag("a", []html.Attribute{{Key: "href", Val: fmt.Sprintf("tg://user?id=%d", entity.User.ID)}})
+	default:
+		return false
+	}
+
+	return true
+}
@@ -26,10 +26,14 @@ type Tag struct {
 }
 
 var (
-	Bold   = Tag{Open: "<b>", Close: "</b>"}
-	Italic = Tag{Open: "<i>", Close: "</i>"}
-	Code   = Tag{Open: "<code>", Close: "</code>"}
-	Pre    = Tag{Open: "<pre>", Close: "</pre>"}
+	Bold          = Tag{Open: "<b>", Close: "</b>"}
+	Italic        = Tag{Open: "<i>", Close: "</i>"}
+	Underline     = Tag{Open: "<u>", Close: "</u>"}
+	Strikethrough = Tag{Open: "<s>", Close: "</s>"}
+	Spoiler       = Tag{Open: "<tg-spoiler>", Close: "</tg-spoiler>"}
+	Code          = Tag{Open: "<code>", Close: "</code>"}
+	Pre           = Tag{Open: "<pre>", Close: "</pre>"}
+	Blockquote    = Tag{Open: "<blockquote>", Close: "</blockquote>"}
 )
 
 type TagConverter interface {
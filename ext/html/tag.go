@@ -10,11 +10,18 @@ func (c PlainTagConverter) Get(name string, _ []html.Attribute) (Tag, bool) {
 }
 
 var DefaultTagConverter = PlainTagConverter{
-	"strong": Bold,
-	"b":      Bold,
-	"italic": Italic,
-	"em":     Italic,
-	"i":      Italic,
-	"code":   Code,
-	"pre":    Pre,
+	"strong":     Bold,
+	"b":          Bold,
+	"italic":     Italic,
+	"em":         Italic,
+	"i":          Italic,
+	"u":          Underline,
+	"ins":        Underline,
+	"s":          Strikethrough,
+	"strike":     Strikethrough,
+	"del":        Strikethrough,
+	"tg-spoiler": Spoiler,
+	"code":       Code,
+	"pre":        Pre,
+	"blockquote": Blockquote,
 }
@@ -52,24 +52,7 @@ func (w *Writer) StartTag(name string, attrs []html.Attribute) *Writer {
 
 	default:
 		if tag, ok := w.getTagConverter().Get(name, attrs); ok {
-			if len(tag.Open)+len(tag.Close)+3 >= w.Out.PageCapacity(w.Context()) {
-				if err := w.Out.BreakPage(w.Context()); err != nil {
-					w.err = err
-					return w
-				}
-			}
-
-			if w.currAnchor != nil {
-				w.currAnchor.text += tag.Open
-			} else {
-				w.Out.Write(tag.Open)
-				w.Out.UpdatePrefix(func(prefix string) string { return prefix + tag.Open })
-				w.Out.UpdateSuffix(func(suffix string) string { return tag.Close + suffix })
-			}
-
-			tag.parent = w.currTag
-			w.currTag = &tag
-			return w
+			return w.openTag(tag)
 		} else {
 			w.currTag = &Tag{parent: w.currTag}
 		}
@@ -78,6 +61,27 @@ func (w *Writer) StartTag(name string, attrs []html.Attribute) *Writer {
 	return w
 }
 
+func (w *Writer) openTag(tag Tag) *Writer {
+	if len(tag.Open)+len(tag.Close)+3 >= w.Out.PageCapacity(w.Context()) {
+		if err := w.Out.BreakPage(w.Context()); err != nil {
+			w.err = err
+			return w
+		}
+	}
+
+	if w.currAnchor != nil {
+		w.currAnchor.text += tag.Open
+	} else {
+		w.Out.Write(tag.Open)
+		w.Out.UpdatePrefix(func(prefix string) string { return prefix + tag.Open })
+		w.Out.UpdateSuffix(func(suffix string) string { return tag.Close + suffix })
+	}
+
+	tag.parent = w.currTag
+	w.currTag = &tag
+	return w
+}
+
 func (w *Writer) Text(text string, args ...interface{}) *Writer {
 	if w.err != nil || w.Out.IsOverflown() {
 		return w
@@ -89,7 +93,7 @@ func (w *Writer) Text(text string, args ...interface{}) *Writer {
 
 	text = html.EscapeString(text)
 	if w.currAnchor != nil {
-		w.currAnchor.text += html.EscapeString(text)
+		w.currAnchor.text += text
 	} else {
 		w.err = w.Out.WriteBreakable(w.Context(), text)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	telegram "github.com/jfk9w-go/telegram-bot-api"
 	tghtml "github.com/jfk9w-go/telegram-bot-api/ext/html"
 	"github.com/jfk9w-go/telegram-bot-api/ext/output"
 	"github.com/jfk9w-go/telegram-bot-api/ext/receiver"
@@ -75,3 +76,26 @@ func TestWriter_Markup_Autofix(t *testing.T) {
 		"<i>соусов: https://pastebin.com/i32h11vd</i>",
 	}, buf.Pages)
 }
+
+func TestWriter_Entities(t *testing.T) {
+	buf := receiver.NewBuffer()
+	writer := (&tghtml.Writer{
+		Out: &output.Paged{Receiver: buf},
+	}).WithContext(output.With(context.Background(), 4096, 0))
+
+	message := &telegram.Message{
+		Text: "👋 Привет, <мир>! spoiler link @user",
+		Entities: []telegram.MessageEntity{
+			{Type: telegram.BoldEntity, Offset: 3, Length: 14},
+			{Type: telegram.ItalicEntity, Offset: 11, Length: 5},
+			{Type: telegram.SpoilerEntity, Offset: 18, Length: 7},
+			{Type: telegram.TextLinkEntity, Offset: 26, Length: 4, URL: "https://example.com/?a=1&b=2"},
+			{Type: telegram.MentionEntity, Offset: 31, Length: 5},
+		},
+	}
+
+	assert.Nil(t, writer.Message(message).Flush())
+	assert.Equal(t, []string{
+		`👋 <b>Привет, <i>&lt;мир&gt;</i>!</b> <tg-spoiler>spoiler</tg-spoiler> <a href="https://example.com/?a=1&amp;b=2">link</a> @user`,
+	}, buf.Pages)
+}
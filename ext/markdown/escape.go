@@ -0,0 +1,33 @@
+package markdown
+
+import "strings"
+
+var (
+	textEscaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+		"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+		"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`)
+	codeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+	urlEscaper  = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+)
+
+// Escape escapes all MarkdownV2 special characters in text.
+// See https://core.telegram.org/bots/api#markdownv2-style
+func Escape(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// EscapeCode escapes text inside code and pre entities.
+func EscapeCode(text string) string {
+	return codeEscaper.Replace(text)
+}
+
+// EscapeURL escapes URL inside inline link definition.
+func EscapeURL(url string) string {
+	return urlEscaper.Replace(url)
+}
+
+// Link returns an inline link.
+func Link(text, href string) string {
+	return "[" + Escape(text) + "](" + EscapeURL(href) + ")"
+}
@@ -0,0 +1,182 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jfk9w-go/flu/syncf"
+	"github.com/jfk9w-go/telegram-bot-api/ext/html"
+	"github.com/jfk9w-go/telegram-bot-api/ext/receiver"
+)
+
+// Tag is a pair of MarkdownV2 entity delimiters.
+type Tag struct {
+	Open, Close string
+	// code is set for entities which contents are escaped with EscapeCode.
+	code   bool
+	parent *Tag
+}
+
+var (
+	Bold          = Tag{Open: "*", Close: "*"}
+	Italic        = Tag{Open: "_", Close: "_"}
+	Underline     = Tag{Open: "__", Close: "__"}
+	Strikethrough = Tag{Open: "~", Close: "~"}
+	Spoiler       = Tag{Open: "||", Close: "||"}
+	Code          = Tag{Open: "`", Close: "`", code: true}
+	Pre           = PreLanguage("")
+)
+
+// PreLanguage returns a pre-formatted code block tag with the specified programming language.
+func PreLanguage(language string) Tag {
+	return Tag{Open: "```" + language + "\n", Close: "\n```", code: true}
+}
+
+// Writer is a MarkdownV2 text builder.
+// It has the same API as html.Writer and writes to the same html.Output,
+// so output.Paged splits pages with open entities closed and reopened on page breaks.
+type Writer struct {
+	Out     html.Output
+	ctx     context.Context
+	currTag *Tag
+	err     error
+}
+
+func (w *Writer) Context() context.Context {
+	if w.ctx == nil {
+		return context.Background()
+	}
+
+	return w.ctx
+}
+
+func (w *Writer) WithContext(ctx context.Context) *Writer {
+	value := *w
+	value.ctx = ctx
+	return &value
+}
+
+func (w *Writer) StartTag(tag Tag) *Writer {
+	if w.err != nil || w.Out.IsOverflown() {
+		return w
+	}
+
+	if len(tag.Open)+len(tag.Close)+3 >= w.Out.PageCapacity(w.Context()) {
+		if err := w.Out.BreakPage(w.Context()); err != nil {
+			w.err = err
+			return w
+		}
+	}
+
+	w.Out.Write(tag.Open)
+	w.Out.UpdatePrefix(func(prefix string) string { return prefix + tag.Open })
+	w.Out.UpdateSuffix(func(suffix string) string { return tag.Close + suffix })
+	tag.parent = w.currTag
+	w.currTag = &tag
+	return w
+}
+
+func (w *Writer) Text(text string, args ...interface{}) *Writer {
+	if w.err != nil || w.Out.IsOverflown() {
+		return w
+	}
+
+	if len(args) > 0 {
+		text = fmt.Sprintf(text, args...)
+	}
+
+	if w.isCode() {
+		text = EscapeCode(text)
+	} else {
+		text = Escape(text)
+	}
+
+	w.err = w.Out.WriteBreakable(w.Context(), text)
+	return w
+}
+
+func (w *Writer) EndTag() *Writer {
+	if w.err != nil || w.Out.IsOverflown() || w.currTag == nil {
+		return w
+	}
+
+	tag := w.currTag
+	w.Out.Write(tag.Close)
+	w.Out.UpdatePrefix(func(prefix string) string { return prefix[:len(prefix)-len(tag.Open)] })
+	w.Out.UpdateSuffix(func(suffix string) string { return suffix[len(tag.Close):] })
+	w.currTag = tag.parent
+	return w
+}
+
+func (w *Writer) Bold(text string, args ...interface{}) *Writer {
+	return w.StartTag(Bold).Text(text, args...).EndTag()
+}
+
+func (w *Writer) Italic(text string, args ...interface{}) *Writer {
+	return w.StartTag(Italic).Text(text, args...).EndTag()
+}
+
+func (w *Writer) Underline(text string, args ...interface{}) *Writer {
+	return w.StartTag(Underline).Text(text, args...).EndTag()
+}
+
+func (w *Writer) Strikethrough(text string, args ...interface{}) *Writer {
+	return w.StartTag(Strikethrough).Text(text, args...).EndTag()
+}
+
+func (w *Writer) Spoiler(text string, args ...interface{}) *Writer {
+	return w.StartTag(Spoiler).Text(text, args...).EndTag()
+}
+
+func (w *Writer) Code(text string, args ...interface{}) *Writer {
+	return w.StartTag(Code).Text(text, args...).EndTag()
+}
+
+func (w *Writer) Pre(text string, args ...interface{}) *Writer {
+	return w.StartTag(Pre).Text(text, args...).EndTag()
+}
+
+func (w *Writer) Link(text, href string) *Writer {
+	if w.err != nil || w.Out.IsOverflown() {
+		return w
+	}
+
+	w.err = w.Out.WriteUnbreakable(w.Context(), Link(text, href))
+	return w
+}
+
+func (w *Writer) Media(url string, ref syncf.Ref[*receiver.Media], collapsible bool, anchored bool) *Writer {
+	if w.err != nil || w.Out.IsOverflown() {
+		return w
+	}
+
+	anchor := ""
+	if anchored {
+		anchor = Link("[media]", url)
+	}
+
+	w.err = w.Out.AddMedia(w.Context(), ref, anchor, collapsible)
+	return w
+}
+
+func (w *Writer) Flush() error {
+	if w.err != nil || w.Out.IsOverflown() {
+		return w.err
+	}
+
+	for w.currTag != nil {
+		w.EndTag()
+	}
+
+	return w.Out.Flush(w.Context())
+}
+
+func (w *Writer) isCode() bool {
+	for tag := w.currTag; tag != nil; tag = tag.parent {
+		if tag.code {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package markdown_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jfk9w-go/telegram-bot-api/ext/markdown"
+	"github.com/jfk9w-go/telegram-bot-api/ext/output"
+	"github.com/jfk9w-go/telegram-bot-api/ext/receiver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_Builder(t *testing.T) {
+	buf := receiver.NewBuffer()
+	writer := (&markdown.Writer{
+		Out: &output.Paged{Receiver: buf},
+	}).WithContext(output.With(context.Background(), 72, 0))
+
+	err := writer.
+		Bold("A Study in Scarlet is an 1887 detective novel by Scottish author Arthur Conan Doyle.").
+		Text(" ").
+		Link("Wikipedia", "https://en.wikipedia.org/wiki/A_Study_in_Scarlet_(novel)").
+		Flush()
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		`*A Study in Scarlet is an 1887 detective novel by Scottish author*`,
+		`*Arthur Conan Doyle\.*`,
+		`[Wikipedia](https://en.wikipedia.org/wiki/A_Study_in_Scarlet_(novel\))`,
+	}, buf.Pages)
+}
+
+func TestWriter_Escape(t *testing.T) {
+	buf := receiver.NewBuffer()
+	writer := (&markdown.Writer{
+		Out: &output.Paged{Receiver: buf},
+	}).WithContext(output.With(context.Background(), 4096, 0))
+
+	err := writer.
+		Text("1+1=2 (really!) ").
+		Italic("snake_case").
+		Text(" ").
+		Code("a*b `c` \\d").
+		Text("\n").
+		Pre("if a > b {\n\treturn a\n}").
+		Flush()
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"1\\+1\\=2 \\(really\\!\\) _snake\\_case_ `a*b \\`c\\` \\\\d`\n```\nif a > b {\n\treturn a\n}\n```",
+	}, buf.Pages)
+}
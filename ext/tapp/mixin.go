@@ -8,12 +8,33 @@ import (
 	"github.com/jfk9w-go/flu"
 	"github.com/jfk9w-go/flu/apfel"
 	"github.com/jfk9w-go/flu/logf"
+	"github.com/jfk9w-go/flu/me3x"
 	"github.com/jfk9w-go/flu/syncf"
 	"github.com/jfk9w-go/telegram-bot-api"
 )
 
 type Config struct {
-	Token string `yaml:"token" doc:"Telegram Bot API token."`
+	Token    string `yaml:"token" doc:"Telegram Bot API token."`
+	Endpoint string `yaml:"endpoint,omitempty" doc:"Telegram Bot API server URL. Useful for self-hosted Bot API servers."`
+	Test     bool   `yaml:"test,omitempty" doc:"Use Telegram test environment."`
+	Local    bool   `yaml:"local,omitempty" doc:"Bot API server is running in --local mode."`
+}
+
+func (c Config) options() []telegram.BotOption {
+	options := make([]telegram.BotOption, 0)
+	if c.Endpoint != "" {
+		options = append(options, telegram.WithEndpoint(c.Endpoint))
+	}
+
+	if c.Test {
+		options = append(options, telegram.WithTestEnvironment())
+	}
+
+	if c.Local {
+		options = append(options, telegram.WithLocalMode())
+	}
+
+	return options
 }
 
 type Context interface {
@@ -26,6 +47,8 @@ type Listener interface {
 }
 
 type Mixin[C Context] struct {
+	// Metrics enables Bot metrics reporting if set.
+	Metrics  me3x.Registry
 	version  string
 	bot      *telegram.Bot
 	commands Commands
@@ -43,7 +66,13 @@ func (m *Mixin[C]) Bot() *telegram.Bot {
 
 func (m *Mixin[C]) Include(ctx context.Context, app apfel.MixinApp[C]) error {
 	m.version = app.Version()
-	m.bot = telegram.NewBot(app, nil, app.Config().TelegramConfig().Token)
+	config := app.Config().TelegramConfig()
+	options := config.options()
+	if m.Metrics != nil {
+		options = append(options, telegram.WithMetrics(m.Metrics))
+	}
+
+	m.bot = telegram.NewBot(app, nil, config.Token, options...)
 	m.commands = make(Commands)
 	m.registry = make(telegram.CommandRegistry)
 	return nil
@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/jfk9w-go/flu/httpf"
+	"github.com/pkg/errors"
+)
+
+// ErrFileTooLarge is returned when the file size exceeds the configured limit.
+// See WithMaxFileSize.
+var ErrFileTooLarge = errors.New("file too large")
+
+// OpenFile calls GetFile and returns a flu.Input for reading file contents.
+// The provided context is used for the download.
+// Absolute file paths returned by a local Bot API server are read directly from the file system
+// (only when WithLocalMode is used, otherwise files are always downloaded over HTTP).
+func (c *baseClient) OpenFile(ctx context.Context, fileID string) (flu.Input, error) {
+	file, err := c.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get file")
+	}
+
+	if file.Path == "" {
+		return nil, errors.New("file is not available for download")
+	}
+
+	if c.maxFileSize > 0 && file.Size > c.maxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	return &fileInput{
+		ctx:     ctx,
+		client:  c.client,
+		url:     c.fileEndpoint(file.Path),
+		path:    file.Path,
+		local:   c.local,
+		maxSize: c.maxFileSize,
+	}, nil
+}
+
+// DownloadFile copies file contents to the flu.Output.
+// It returns the number of bytes written.
+func (c *baseClient) DownloadFile(ctx context.Context, fileID string, out flu.Output) (int64, error) {
+	in, err := c.OpenFile(ctx, fileID)
+	if err != nil {
+		return 0, err
+	}
+
+	return flu.Copy(in, out)
+}
+
+type fileInput struct {
+	ctx     context.Context
+	client  httpf.Client
+	url     string
+	path    string
+	local   bool
+	maxSize int64
+}
+
+func (i *fileInput) Reader() (io.Reader, error) {
+	if i.local && filepath.IsAbs(i.path) {
+		file, err := os.Open(i.path)
+		if err != nil {
+			return nil, errors.Wrap(err, "open local file")
+		}
+
+		return &limitedReader{ctx: i.ctx, reader: file, limit: i.maxSize}, nil
+	}
+
+	resp := httpf.GET(i.url).
+		Exchange(i.ctx, i.client).
+		CheckStatus(http.StatusOK)
+	body, err := resp.Reader()
+	if err != nil {
+		_ = resp.Error()
+		return nil, errors.Wrap(err, "download file")
+	}
+
+	if i.maxSize > 0 && resp.ContentLength > i.maxSize {
+		_ = resp.Error()
+		return nil, ErrFileTooLarge
+	}
+
+	return &limitedReader{ctx: i.ctx, reader: body, limit: i.maxSize}, nil
+}
+
+// limitedReader fails with ErrFileTooLarge after reading more than limit bytes.
+// It also checks for context cancellation on every read.
+type limitedReader struct {
+	ctx    context.Context
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+func (r *limitedReader) Read(data []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := r.reader.Read(data)
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		return n, ErrFileTooLarge
+	}
+
+	return n, err
+}
+
+func (r *limitedReader) Close() error {
+	return flu.Close(r.reader)
+}
@@ -0,0 +1,41 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitedReader_Limit(t *testing.T) {
+	reader := &limitedReader{ctx: context.Background(), reader: bytes.NewReader([]byte("contents")), limit: 8}
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "contents", string(data))
+
+	reader = &limitedReader{ctx: context.Background(), reader: bytes.NewReader([]byte("contents")), limit: 7}
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+
+	// Non-positive limit means no limit.
+	reader = &limitedReader{ctx: context.Background(), reader: bytes.NewReader([]byte("contents")), limit: -1}
+	data, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "contents", string(data))
+}
+
+func TestLimitedReader_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &limitedReader{ctx: ctx, reader: bytes.NewReader([]byte("contents"))}
+	buf := make([]byte, 4)
+	n, err := reader.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+
+	cancel()
+	n, err = reader.Read(buf)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, n)
+}
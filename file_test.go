@@ -0,0 +1,74 @@
+package telegram_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBot_DownloadFile(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	fileID := f.Server.AddFile([]byte("contents"))
+
+	buf := new(flu.ByteBuffer)
+	n, err := f.Bot.DownloadFile(timeout(t), fileID, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), n)
+	assert.Equal(t, "contents", buf.Unmask().String())
+}
+
+func TestBot_DownloadFileTooLarge(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock, telegram.WithMaxFileSize(4))
+	fileID := f.Server.AddFile([]byte("contents"))
+
+	_, err := f.Bot.DownloadFile(timeout(t), fileID, new(flu.ByteBuffer))
+	assert.ErrorIs(t, err, telegram.ErrFileTooLarge)
+}
+
+func TestBot_OpenFileCancelled(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	fileID := f.Server.AddFile([]byte("contents"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in, err := f.Bot.OpenFile(ctx, fileID)
+	assert.NoError(t, err)
+
+	cancel()
+	_, err = flu.Copy(in, new(flu.ByteBuffer))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBot_OpenFileAbsolutePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(path, []byte("secret"), 0600))
+
+	for _, local := range []bool{false, true} {
+		var options []telegram.BotOption
+		if local {
+			options = append(options, telegram.WithLocalMode())
+		}
+
+		f := telegramtest.Setup(t, syncf.DefaultClock, options...)
+		f.Server.Handle("getFile", func(call *telegramtest.Call) (interface{}, error) {
+			return telegram.File{ID: "secret", UniqueID: "secret", Size: 6, Path: path}, nil
+		})
+
+		buf := new(flu.ByteBuffer)
+		_, err := f.Bot.DownloadFile(timeout(t), "secret", buf)
+		if local {
+			assert.NoError(t, err)
+			assert.Equal(t, "secret", buf.Unmask().String())
+		} else {
+			// Only a local server may point to the local file system.
+			assert.Error(t, err)
+			assert.Empty(t, buf.Unmask().String())
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package telegram_test
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// timeout returns a context which is cancelled after a reasonable test timeout.
+func timeout(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// receive returns the next value from the channel.
+// The test fails if nothing is received within a reasonable test timeout.
+func receive[T any](t *testing.T, values <-chan T, what string) T {
+	t.Helper()
+	select {
+	case value := <-values:
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s was not received", what)
+		panic("unreachable")
+	}
+}
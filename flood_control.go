@@ -4,47 +4,41 @@ import (
 	"context"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/jfk9w-go/flu/logf"
 
 	"github.com/jfk9w-go/flu"
+	"github.com/jfk9w-go/flu/httpf"
 	"github.com/jfk9w-go/flu/syncf"
 	"github.com/pkg/errors"
 )
 
-// GatewaySendDelay is a delay between two consecutive /send* API calls per bot token.
-var GatewaySendDelay = 35 * time.Millisecond
-
-// SendDelays are delays between two consecutive /send* API calls per chat with a given type.
-var SendDelays = map[ChatType]time.Duration{
-	PrivateChat: 35 * time.Millisecond,
-	GroupChat:   3 * time.Second,
-	Supergroup:  time.Second,
-	Channel:     3 * time.Second,
-}
-
-var MaxSendRetries = 3
-
 type executor interface {
 	Execute(ctx context.Context, method string, body flu.EncoderTo, resp interface{}) error
 }
 
 type floodControlAware struct {
-	clock    syncf.Clock
-	executor executor
-	lock     syncf.Locker
-	locks    map[ChatID]syncf.Locker
-	once     sync.Once
-	mu       syncf.RWMutex
+	clock        syncf.Clock
+	executor     executor
+	local        bool
+	chatMigrated ChatMigrationFunc
+	limits       RateLimits
+	retry        RetryPolicy
+	metrics      metrics
+	global       *tokenBucket
+	queue        priorityQueue
+	chatTypes    map[ChatType]*tokenBucket
+	chats        map[ChatID]*chatLimiter
+	once         sync.Once
+	mu           syncf.RWMutex
 }
 
 var errUnknownRecipient = errors.New("unknown recipient")
 
 func (c *floodControlAware) send(ctx context.Context, chatID ChatID, item sendable, options *SendOptions, resp interface{}) error {
-	c.once.Do(func() {
-		c.lock = syncf.Semaphore(c.clock, 1, GatewaySendDelay)
-	})
+	if c.local {
+		item = localize(item)
+	}
 
 	body, err := options.body(chatID, item)
 	if err != nil {
@@ -52,73 +46,183 @@ func (c *floodControlAware) send(ctx context.Context, chatID ChatID, item sendab
 	}
 
 	method := "send" + strings.Title(item.kind())
-	lock, ok := c.getLock(chatID)
-	if ok {
-		ctx, cancel := lock.Lock(ctx)
-		if ctx.Err() != nil {
-			return ctx.Err()
+	err = c.execute(ctx, chatID, method, body, resp)
+	var migrated ChatMigrated
+	if errors.As(err, &migrated) && c.chatMigrated != nil {
+		c.chatMigrated(ctx, chatID, migrated.MigrateToChatID)
+		chatID = migrated.MigrateToChatID
+		if body, err = options.body(chatID, item); err != nil {
+			return errors.Wrap(err, "failed to write send data")
 		}
 
-		defer cancel()
+		err = c.execute(ctx, chatID, method, body, resp)
 	}
 
-	ctx, cancel := c.lock.Lock(ctx)
-	if ctx.Err() != nil {
-		return ctx.Err()
+	return err
+}
+
+// execute runs the API call under flood control.
+// chatID may be nil if the call does not target a specific chat (e.g. inline message editing),
+// in which case only the global limit is applied.
+// errUnknownRecipient is returned on success if there is no limiter for the chat yet.
+func (c *floodControlAware) execute(ctx context.Context, chatID ChatID, method string, body flu.EncoderTo, resp interface{}) error {
+	c.once.Do(c.init)
+
+	var (
+		limiter  *chatLimiter
+		ok       = chatID == nil
+		priority = contextPriority(ctx)
+	)
+
+	c.queue.enter(priority)
+	queued := true
+	defer func() {
+		if queued {
+			c.queue.exit(priority)
+		}
+	}()
+
+	if chatID != nil {
+		limiter, ok = c.getLimiter(chatID)
 	}
 
-	defer cancel()
+	// the chat lock is taken only after passing the priority queue,
+	// so that queued lower priority requests do not hold up higher priority requests to the same chat
+	locked := false
+	unlock := context.CancelFunc(func() {})
+	defer func() { unlock() }()
+
+	// retries are made here so that rate limiters may adapt to retry_after
+	policy := contextRetryPolicy(ctx, c.retry)
+	chatType := limiterKind(chatID, limiter)
+	for attempt := 1; ; attempt++ {
+		start := c.clock.Now()
+		if err := c.waitGlobal(ctx, priority); err != nil {
+			return err
+		}
+
+		if limiter != nil {
+			if !locked {
+				ctx, unlock = limiter.lock.Lock(ctx)
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				locked = true
+			}
+
+			if err := limiter.wait(ctx); err != nil {
+				return err
+			}
+		}
 
-	for i := 0; i <= MaxSendRetries; i++ {
-		err = c.executor.Execute(ctx, method, body, resp)
-		var timeout time.Duration
-		switch err := err.(type) {
-		case nil:
+		c.metrics.floodWait(chatType, c.clock.Now().Sub(start))
+
+		if queued {
+			c.queue.exit(priority)
+			queued = false
+		}
+
+		err := c.executor.Execute(WithRetryPolicy(ctx, RetryPolicy{MaxAttempts: 1}), method, body, resp)
+		if err == nil {
 			if ok {
 				return nil
 			} else {
 				return errUnknownRecipient
 			}
-		case TooManyMessages:
-			logf.Get(c).Warnf(ctx, "too many messages, sleeping for %s", err.RetryAfter)
-			timeout = err.RetryAfter
-		case Error:
+		}
+
+		timeout, retry := policy.next(attempt, err)
+		if !retry {
 			return err
-		default:
-			timeout = GatewaySendDelay
+		}
+
+		var tooManyMessages TooManyMessages
+		if errors.As(err, &tooManyMessages) {
+			logf.Get(c).Warnf(ctx, "too many messages, sleeping for %s", tooManyMessages.RetryAfter)
+			c.metrics.retryAfter(chatType)
+			switch {
+			case limiter != nil:
+				limiter.chat.pause(tooManyMessages.RetryAfter)
+				continue
+			case chatID == nil:
+				c.global.pause(tooManyMessages.RetryAfter)
+				continue
+			}
 		}
 
 		if err := flu.Sleep(ctx, timeout); err != nil {
 			return err
 		}
 	}
+}
 
-	return err
+// limiterKind returns the chat type label for metrics.
+func limiterKind(chatID ChatID, limiter *chatLimiter) string {
+	switch {
+	case chatID == nil:
+		return "none"
+	case limiter == nil:
+		return "unknown"
+	default:
+		return string(limiter.kind)
+	}
+}
+
+func (c *floodControlAware) waitGlobal(ctx context.Context, priority Priority) error {
+	if c.global == nil {
+		return nil
+	}
+
+	if err := c.queue.acquire(ctx, priority); err != nil {
+		return err
+	}
+
+	defer c.queue.release()
+	return c.global.wait(ctx)
+}
+
+func (c *floodControlAware) init() {
+	if c.clock == nil {
+		c.clock = syncf.DefaultClock
+	}
+
+	c.global = newTokenBucket(c.clock, c.limits.Global)
+	c.chatTypes = make(map[ChatType]*tokenBucket)
+	for chatType, limit := range c.limits.ChatType {
+		c.chatTypes[chatType] = newTokenBucket(c.clock, limit)
+	}
 }
 
-func (c *floodControlAware) getLock(chatID ChatID) (syncf.Locker, bool) {
+func (c *floodControlAware) getLimiter(chatID ChatID) (*chatLimiter, bool) {
 	_, cancel := c.mu.RLock(nil)
 	defer cancel()
-	lock, ok := c.locks[chatID]
-	return lock, ok
+	limiter, ok := c.chats[chatID]
+	return limiter, ok
 }
 
-func (c *floodControlAware) createLock(chat *Chat) {
+func (c *floodControlAware) createLimiter(chat *Chat) {
 	_, cancel := c.mu.Lock(nil)
 	defer cancel()
 
-	if _, ok := c.locks[chat.ID]; ok {
+	if _, ok := c.chats[chat.ID]; ok {
 		return
 	}
 
-	if c.locks == nil {
-		c.locks = make(map[ChatID]syncf.Locker)
+	if c.chats == nil {
+		c.chats = make(map[ChatID]*chatLimiter)
+	}
+
+	limiter := &chatLimiter{
+		lock:     syncf.Semaphore(c.clock, 1, 0),
+		chat:     newTokenBucket(c.clock, c.limits.Chat[chat.Type]),
+		chatType: c.chatTypes[chat.Type],
+		kind:     chat.Type,
 	}
 
-	lock := syncf.Semaphore(c.clock, 1, chat.Type.SendDelay())
-	c.locks[chat.ID] = lock
+	c.chats[chat.ID] = limiter
 	if chat.Username != nil {
-		c.locks[*chat.Username] = lock
+		c.chats[*chat.Username] = limiter
 	}
 }
 
@@ -135,7 +239,7 @@ func (c *floodControlAware) Send(ctx context.Context, chatID ChatID, item Sendab
 	m := new(Message)
 	err := c.send(ctx, chatID, item, options, m)
 	if err == errUnknownRecipient {
-		c.createLock(&m.Chat)
+		c.createLimiter(&m.Chat)
 		err = nil
 	}
 
@@ -149,9 +253,113 @@ func (c *floodControlAware) SendMediaGroup(ctx context.Context, chatID ChatID, m
 	ms := make([]Message, 0)
 	err := c.send(ctx, chatID, MediaGroup(media), options, &ms)
 	if err == errUnknownRecipient {
-		c.createLock(&ms[0].Chat)
+		c.createLimiter(&ms[0].Chat)
 		err = nil
 	}
 
 	return ms, err
 }
+
+// ForwardMessage is used to forward messages of any kind.
+// Service messages can't be forwarded.
+// On success, the ID of the sent Message is returned.
+// See https://core.telegram.org/bots/api#forwardmessage
+func (c *floodControlAware) ForwardMessage(ctx context.Context, chatID ChatID, ref MessageRef, options *SendOptions) (ID, error) {
+	form, err := options.body(chatID, ref)
+	if err != nil {
+		return 0, err
+	}
+
+	m := new(Message)
+	err = c.execute(ctx, chatID, "forwardMessage", form, m)
+	if err == errUnknownRecipient {
+		c.createLimiter(&m.Chat)
+		err = nil
+	}
+
+	return m.ID, err
+}
+
+// CopyMessage is used to copy messages of any kind.
+// The method is analogous to ForwardMessage, but the copied message doesn't have a link to the original message.
+// Returns the ID of the sent message on success.
+// See https://core.telegram.org/bots/api#copymessage
+func (c *floodControlAware) CopyMessage(ctx context.Context, chatID ChatID, ref MessageRef, options *CopyOptions) (ID, error) {
+	var resp struct {
+		MessageID ID `json:"message_id"`
+	}
+
+	form, err := options.body(chatID, ref)
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.executeChat(ctx, chatID, "copyMessage", form, &resp)
+	return resp.MessageID, err
+}
+
+// DeleteMessage is used to delete a message, including service messages, with the following limitations:
+// - A message can only be deleted if it was sent less than 48 hours ago.
+// - Bots can delete outgoing messages in private chats, groups, and supergroups.
+// - Bots granted can_post_messages permissions can delete outgoing messages in channels.
+// - If the bot is an administrator of a group, it can delete any message there.
+// - If the bot has can_delete_messages permission in a supergroup or a channel, it can delete any message there.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#deletemessage
+func (c *floodControlAware) DeleteMessage(ctx context.Context, ref MessageRef) error {
+	var ok bool
+	if err := c.executeChat(ctx, ref.ChatID, "deleteMessage", ref.form(), &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// SendChatAction is used to tell the user that something is happening on the bot's side.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#sendchataction
+func (c *floodControlAware) SendChatAction(ctx context.Context, chatID ChatID, action string) error {
+	body := new(httpf.Form).
+		Set("chat_id", chatID.queryParam()).
+		Set("action", action)
+	var ok bool
+	if err := c.executeChat(ctx, chatID, "sendChatAction", body, &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// executeChat runs the chat-targeting API call under flood control.
+// If there is no limiter for the chat yet, the chat is requested with learnChat.
+func (c *floodControlAware) executeChat(ctx context.Context, chatID ChatID, method string, body flu.EncoderTo, resp interface{}) error {
+	err := c.execute(ctx, chatID, method, body, resp)
+	if err == errUnknownRecipient {
+		c.learnChat(ctx, chatID)
+		return nil
+	}
+
+	return err
+}
+
+// learnChat requests chat info in order to create the chat limiter
+// for API calls which do not return the chat in response.
+func (c *floodControlAware) learnChat(ctx context.Context, chatID ChatID) {
+	body := new(httpf.Form).
+		Set("chat_id", chatID.queryParam())
+	chat := new(Chat)
+	if err := c.execute(ctx, nil, "getChat", body, chat); err != nil {
+		logf.Get(c).Warnf(ctx, "get chat %s: %v", chatID, err)
+		return
+	}
+
+	c.createLimiter(chat)
+}
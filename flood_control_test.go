@@ -0,0 +1,56 @@
+package telegram_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBot_SendErrors(t *testing.T) {
+	var migrations []telegram.ID
+	f := telegramtest.Setup(t, syncf.DefaultClock,
+		telegram.WithChatMigration(func(ctx context.Context, from telegram.ChatID, to telegram.ID) {
+			migrations = append(migrations, to)
+		}))
+
+	server, bot, user, chat := f.Server, f.Bot, f.User, f.Chat
+	server.AddChat(telegram.Chat{ID: -2, Type: telegram.GroupChat, Title: "Group"})
+	ctx := timeout(t)
+
+	server.FloodWait("sendMessage", 1, 1)
+	start := time.Now()
+	_, err := bot.Send(ctx, chat.ID, telegram.Text{Text: "flood"}, nil)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) >= time.Second)
+	assert.Len(t, server.Calls("sendMessage"), 2)
+
+	server.Block(user.ID)
+	_, err = bot.Send(ctx, chat.ID, telegram.Text{Text: "blocked"}, nil)
+	assert.Equal(t, telegram.Error{ErrorCode: 403, Description: "Forbidden: bot was blocked by the user"}, err)
+
+	supergroup := server.Migrate(-2)
+	message, err := bot.Send(ctx, telegram.ID(-2), telegram.Text{Text: "migrated"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, supergroup.ID, message.Chat.ID)
+	assert.Equal(t, []telegram.ID{supergroup.ID}, migrations)
+}
+
+func TestBot_SendChatAction(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	ctx := timeout(t)
+	assert.Nil(t, f.Bot.SendChatAction(ctx, f.Chat.ID, "typing"))
+	assert.Nil(t, f.Bot.SendChatAction(ctx, f.Chat.ID, "typing"))
+
+	// The chat is learned through flood control on the first request.
+	var methods []string
+	for _, call := range f.Server.Calls("sendChatAction", "getChat") {
+		methods = append(methods, call.Method)
+	}
+
+	assert.Equal(t, []string{"sendChatAction", "getChat", "sendChatAction"}, methods)
+}
@@ -0,0 +1,263 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// InlineQuery (https://core.telegram.org/bots/api#inlinequery)
+	InlineQuery struct {
+		ID       string    `json:"id"`
+		From     User      `json:"from"`
+		Query    string    `json:"query"`
+		Offset   string    `json:"offset"`
+		ChatType ChatType  `json:"chat_type"`
+		Location *Location `json:"location"`
+	}
+
+	// ChosenInlineResult (https://core.telegram.org/bots/api#choseninlineresult)
+	ChosenInlineResult struct {
+		ResultID        string           `json:"result_id"`
+		From            User             `json:"from"`
+		Location        *Location        `json:"location"`
+		InlineMessageID *InlineMessageID `json:"inline_message_id"`
+		Query           string           `json:"query"`
+	}
+
+	// InlineQueryResultsButton (https://core.telegram.org/bots/api#inlinequeryresultsbutton)
+	InlineQueryResultsButton struct {
+		Text           string `json:"text"`
+		StartParameter string `json:"start_parameter,omitempty"`
+	}
+
+	// InputMessageContent (https://core.telegram.org/bots/api#inputmessagecontent)
+	InputMessageContent interface {
+		self() InputMessageContent
+	}
+
+	// InputTextMessageContent (https://core.telegram.org/bots/api#inputtextmessagecontent)
+	InputTextMessageContent struct {
+		MessageText           string    `json:"message_text"`
+		ParseMode             ParseMode `json:"parse_mode,omitempty"`
+		DisableWebPagePreview bool      `json:"disable_web_page_preview,omitempty"`
+	}
+
+	// InlineQueryResult (https://core.telegram.org/bots/api#inlinequeryresult)
+	InlineQueryResult interface {
+		json.Marshaler
+		self() InlineQueryResult
+	}
+
+	// InlineQueryResultArticle (https://core.telegram.org/bots/api#inlinequeryresultarticle)
+	InlineQueryResultArticle struct {
+		ID                  string                `json:"id"`
+		Title               string                `json:"title"`
+		InputMessageContent InputMessageContent   `json:"input_message_content"`
+		ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+		URL                 string                `json:"url,omitempty"`
+		HideURL             bool                  `json:"hide_url,omitempty"`
+		Description         string                `json:"description,omitempty"`
+		ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+		ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+		ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+	}
+
+	// InlineQueryResultPhoto (https://core.telegram.org/bots/api#inlinequeryresultphoto)
+	InlineQueryResultPhoto struct {
+		ID                  string                `json:"id"`
+		PhotoURL            string                `json:"photo_url"`
+		ThumbnailURL        string                `json:"thumbnail_url"`
+		PhotoWidth          int                   `json:"photo_width,omitempty"`
+		PhotoHeight         int                   `json:"photo_height,omitempty"`
+		Title               string                `json:"title,omitempty"`
+		Description         string                `json:"description,omitempty"`
+		Caption             string                `json:"caption,omitempty"`
+		ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+		ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+		InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	}
+
+	// InlineQueryResultGif (https://core.telegram.org/bots/api#inlinequeryresultgif)
+	InlineQueryResultGif struct {
+		ID                  string                `json:"id"`
+		GifURL              string                `json:"gif_url"`
+		GifWidth            int                   `json:"gif_width,omitempty"`
+		GifHeight           int                   `json:"gif_height,omitempty"`
+		GifDuration         int                   `json:"gif_duration,omitempty"`
+		ThumbnailURL        string                `json:"thumbnail_url"`
+		ThumbnailMIMEType   string                `json:"thumbnail_mime_type,omitempty"`
+		Title               string                `json:"title,omitempty"`
+		Caption             string                `json:"caption,omitempty"`
+		ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+		ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+		InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	}
+
+	// InlineQueryResultVideo (https://core.telegram.org/bots/api#inlinequeryresultvideo)
+	InlineQueryResultVideo struct {
+		ID                  string                `json:"id"`
+		VideoURL            string                `json:"video_url"`
+		MIMEType            string                `json:"mime_type"`
+		ThumbnailURL        string                `json:"thumbnail_url"`
+		Title               string                `json:"title"`
+		Caption             string                `json:"caption,omitempty"`
+		ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+		VideoWidth          int                   `json:"video_width,omitempty"`
+		VideoHeight         int                   `json:"video_height,omitempty"`
+		VideoDuration       int                   `json:"video_duration,omitempty"`
+		Description         string                `json:"description,omitempty"`
+		ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+		InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	}
+
+	// InlineQueryResultDocument (https://core.telegram.org/bots/api#inlinequeryresultdocument)
+	InlineQueryResultDocument struct {
+		ID                  string                `json:"id"`
+		Title               string                `json:"title"`
+		Caption             string                `json:"caption,omitempty"`
+		ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+		DocumentURL         string                `json:"document_url"`
+		MIMEType            string                `json:"mime_type"`
+		Description         string                `json:"description,omitempty"`
+		ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+		InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+		ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+		ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+		ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+	}
+
+	// InlineQueryResultCached is a result for a file stored on the Telegram servers.
+	// The result type and file ID field name are derived from Type.
+	// See https://core.telegram.org/bots/api#inlinequeryresultcachedphoto and below.
+	InlineQueryResultCached struct {
+		Type                MediaType             `json:"-"`
+		ID                  string                `json:"id"`
+		FileID              string                `json:"-"`
+		Title               string                `json:"title,omitempty"`
+		Description         string                `json:"description,omitempty"`
+		Caption             string                `json:"caption,omitempty"`
+		ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+		ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+		InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	}
+
+	// AnswerInlineQueryOptions is /answerInlineQuery request options.
+	// See https://core.telegram.org/bots/api#answerinlinequery
+	AnswerInlineQueryOptions struct {
+		// The maximum amount of time in seconds that the result of the inline query may be cached on the server.
+		// Defaults to 300.
+		CacheTime int `json:"cache_time,omitempty"`
+		// Pass true if results may be cached on the server side only for the user that sent the query.
+		IsPersonal bool `json:"is_personal,omitempty"`
+		// Pass the offset that a client should send in the next query with the same text to receive more results.
+		NextOffset string `json:"next_offset,omitempty"`
+		// An object describing a button to be shown above inline query results.
+		Button *InlineQueryResultsButton `json:"button,omitempty"`
+	}
+)
+
+func (c InputTextMessageContent) self() InputMessageContent {
+	return c
+}
+
+// marshalInlineQueryResult encodes value as JSON object with additional fields (including "type").
+// value must not implement json.Marshaler itself to avoid recursion.
+func marshalInlineQueryResult(value interface{}, fields map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	object := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	for key, value := range fields {
+		if object[key], err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(object)
+}
+
+func (r InlineQueryResultArticle) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultArticle
+	return marshalInlineQueryResult(alias(r), map[string]interface{}{"type": "article"})
+}
+
+func (r InlineQueryResultArticle) self() InlineQueryResult {
+	return r
+}
+
+func (r InlineQueryResultPhoto) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultPhoto
+	return marshalInlineQueryResult(alias(r), map[string]interface{}{"type": "photo"})
+}
+
+func (r InlineQueryResultPhoto) self() InlineQueryResult {
+	return r
+}
+
+func (r InlineQueryResultGif) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultGif
+	return marshalInlineQueryResult(alias(r), map[string]interface{}{"type": "gif"})
+}
+
+func (r InlineQueryResultGif) self() InlineQueryResult {
+	return r
+}
+
+func (r InlineQueryResultVideo) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultVideo
+	return marshalInlineQueryResult(alias(r), map[string]interface{}{"type": "video"})
+}
+
+func (r InlineQueryResultVideo) self() InlineQueryResult {
+	return r
+}
+
+func (r InlineQueryResultDocument) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultDocument
+	return marshalInlineQueryResult(alias(r), map[string]interface{}{"type": "document"})
+}
+
+func (r InlineQueryResultDocument) self() InlineQueryResult {
+	return r
+}
+
+func (r InlineQueryResultCached) MarshalJSON() ([]byte, error) {
+	var resultType string
+	switch r.Type {
+	case Photo, Video, Document, Audio, Sticker, Voice:
+		resultType = string(r.Type)
+	case Animation:
+		resultType = "gif"
+	default:
+		return nil, errors.Errorf("unsupported cached result type: %s", r.Type)
+	}
+
+	type alias InlineQueryResultCached
+	return marshalInlineQueryResult(alias(r), map[string]interface{}{
+		"type":                  resultType,
+		resultType + "_file_id": r.FileID,
+	})
+}
+
+func (r InlineQueryResultCached) self() InlineQueryResult {
+	return r
+}
+
+type InlineQueryListener interface {
+	OnInlineQuery(ctx context.Context, client Client, query *InlineQuery) error
+}
+
+type InlineQueryListenerFunc func(context.Context, Client, *InlineQuery) error
+
+func (fun InlineQueryListenerFunc) OnInlineQuery(ctx context.Context, client Client, query *InlineQuery) error {
+	return fun(ctx, client, query)
+}
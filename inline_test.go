@@ -0,0 +1,105 @@
+package telegram_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineQueryResultCached_JSON(t *testing.T) {
+	for _, tc := range []struct {
+		mediaType telegram.MediaType
+		expected  string
+	}{
+		{telegram.Photo, `{"type":"photo","id":"1","photo_file_id":"file"}`},
+		{telegram.Animation, `{"type":"gif","id":"1","gif_file_id":"file"}`},
+		{telegram.Video, `{"type":"video","id":"1","video_file_id":"file"}`},
+		{telegram.Document, `{"type":"document","id":"1","document_file_id":"file"}`},
+		{telegram.Audio, `{"type":"audio","id":"1","audio_file_id":"file"}`},
+		{telegram.Sticker, `{"type":"sticker","id":"1","sticker_file_id":"file"}`},
+		{telegram.Voice, `{"type":"voice","id":"1","voice_file_id":"file"}`},
+	} {
+		t.Run(string(tc.mediaType), func(t *testing.T) {
+			data, err := json.Marshal(telegram.InlineQueryResultCached{Type: tc.mediaType, ID: "1", FileID: "file"})
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, tc.expected, string(data))
+			}
+		})
+	}
+
+	t.Run("input message content", func(t *testing.T) {
+		data, err := json.Marshal(telegram.InlineQueryResultCached{
+			Type:        telegram.Document,
+			ID:          "1",
+			FileID:      "file",
+			Title:       "title",
+			Caption:     "<b>caption</b>",
+			ParseMode:   telegram.HTML,
+			ReplyMarkup: &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{{{Text: "text", CallbackData: "data"}}}},
+			InputMessageContent: telegram.InputTextMessageContent{
+				MessageText: "hello",
+			},
+		})
+
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{
+				"type": "document",
+				"id": "1",
+				"document_file_id": "file",
+				"title": "title",
+				"caption": "<b>caption</b>",
+				"parse_mode": "HTML",
+				"reply_markup": {"inline_keyboard": [[{"text": "text", "callback_data": "data"}]]},
+				"input_message_content": {"message_text": "hello"}
+			}`, string(data))
+		}
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := json.Marshal(telegram.InlineQueryResultCached{Type: "video_note", ID: "1", FileID: "file"})
+		assert.Error(t, err)
+	})
+}
+
+func TestInlineQueryResult_JSON(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		result   telegram.InlineQueryResult
+		expected string
+	}{
+		{
+			"article",
+			telegram.InlineQueryResultArticle{ID: "1", Title: "title", InputMessageContent: telegram.InputTextMessageContent{MessageText: "hello"}},
+			`{"type":"article","id":"1","title":"title","input_message_content":{"message_text":"hello"}}`,
+		},
+		{
+			"photo",
+			telegram.InlineQueryResultPhoto{ID: "1", PhotoURL: "https://example.com/photo.jpg", ThumbnailURL: "https://example.com/thumb.jpg"},
+			`{"type":"photo","id":"1","photo_url":"https://example.com/photo.jpg","thumbnail_url":"https://example.com/thumb.jpg"}`,
+		},
+		{
+			"gif",
+			telegram.InlineQueryResultGif{ID: "1", GifURL: "https://example.com/anim.gif", ThumbnailURL: "https://example.com/thumb.jpg"},
+			`{"type":"gif","id":"1","gif_url":"https://example.com/anim.gif","thumbnail_url":"https://example.com/thumb.jpg"}`,
+		},
+		{
+			"video",
+			telegram.InlineQueryResultVideo{ID: "1", VideoURL: "https://example.com/video.mp4", MIMEType: "video/mp4", ThumbnailURL: "https://example.com/thumb.jpg", Title: "title"},
+			`{"type":"video","id":"1","video_url":"https://example.com/video.mp4","mime_type":"video/mp4","thumbnail_url":"https://example.com/thumb.jpg","title":"title"}`,
+		},
+		{
+			"document",
+			telegram.InlineQueryResultDocument{ID: "1", Title: "title", DocumentURL: "https://example.com/doc.pdf", MIMEType: "application/pdf"},
+			`{"type":"document","id":"1","title":"title","document_url":"https://example.com/doc.pdf","mime_type":"application/pdf"}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.result)
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, tc.expected, string(data))
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	"github.com/jfk9w-go/flu"
+)
+
+// Call is a single Bot API call passing through the interceptor chain.
+type Call struct {
+	// Method is the Bot API method name.
+	Method string
+	// Body is the request body. Interceptors may replace it before invoking the next one.
+	Body flu.EncoderTo
+	// Response is the value which the call result is decoded into.
+	Response interface{}
+	// Duration is the duration of the HTTP exchange.
+	// It is set after the call has been executed.
+	Duration time.Duration
+}
+
+// Invoker executes a Call.
+type Invoker func(ctx context.Context, call *Call) error
+
+// Interceptor is called around every Bot API call (every retry attempt is a separate call).
+// It may inspect or modify the call before invoking next and inspect the response and the error afterwards.
+// An interceptor may also return without invoking next (for example, to act as a test double).
+type Interceptor func(ctx context.Context, call *Call, next Invoker) error
+
+// CallObserver is used to observe completed Bot API calls.
+type CallObserver func(ctx context.Context, call *Call, err error)
+
+// Observe returns an Interceptor which passes every completed call to observer.
+func Observe(observer CallObserver) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		err := next(ctx, call)
+		observer(ctx, call, err)
+		return err
+	}
+}
+
+// chain wraps invoker with interceptors so that the first interceptor is the outermost one.
+func chain(invoker Invoker, interceptors []Interceptor) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, call *Call) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+
+	return invoker
+}
@@ -0,0 +1,39 @@
+package telegram_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithInterceptors(t *testing.T) {
+	var methods []string
+	stub := func(ctx context.Context, call *telegram.Call, next telegram.Invoker) error {
+		if call.Method == "getMe" {
+			*call.Response.(*telegram.User) = telegram.User{ID: 42, IsBot: true}
+			return nil
+		}
+
+		return next(ctx, call)
+	}
+
+	f := telegramtest.Setup(t, syncf.DefaultClock, telegram.WithInterceptors(
+		telegram.Observe(func(ctx context.Context, call *telegram.Call, err error) {
+			methods = append(methods, call.Method)
+		}),
+		stub))
+
+	ctx := timeout(t)
+	me, err := f.Bot.GetMe(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, telegram.ID(42), me.ID)
+	assert.Empty(t, f.Server.Calls("getMe"))
+
+	_, err = f.Bot.GetChat(ctx, telegram.ID(100))
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"getMe", "getChat"}, methods)
+}
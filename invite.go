@@ -0,0 +1,136 @@
+package telegram
+
+import (
+	"context"
+
+	"github.com/jfk9w-go/flu/httpf"
+	"github.com/pkg/errors"
+)
+
+type (
+	// ChatInviteLink (https://core.telegram.org/bots/api#chatinvitelink)
+	ChatInviteLink struct {
+		InviteLink              string `json:"invite_link"`
+		Creator                 User   `json:"creator"`
+		CreatesJoinRequest      bool   `json:"creates_join_request"`
+		IsPrimary               bool   `json:"is_primary"`
+		IsRevoked               bool   `json:"is_revoked"`
+		Name                    string `json:"name"`
+		ExpireDate              int64  `json:"expire_date"`
+		MemberLimit             int    `json:"member_limit"`
+		PendingJoinRequestCount int    `json:"pending_join_request_count"`
+	}
+
+	// ChatJoinRequest (https://core.telegram.org/bots/api#chatjoinrequest)
+	ChatJoinRequest struct {
+		Chat       Chat            `json:"chat"`
+		From       User            `json:"from"`
+		UserChatID ID              `json:"user_chat_id"`
+		Date       int             `json:"date"`
+		Bio        string          `json:"bio"`
+		InviteLink *ChatInviteLink `json:"invite_link"`
+	}
+)
+
+// InviteLinkOptions is /createChatInviteLink and /editChatInviteLink request options.
+type InviteLinkOptions struct {
+	// Invite link name; 0-32 characters.
+	Name string `url:"name,omitempty"`
+	// Point in time (Unix timestamp) when the link will expire.
+	ExpireDate int64 `url:"expire_date,omitempty"`
+	// The maximum number of users that can be members of the chat simultaneously after joining the chat via this invite link; 1-99999.
+	MemberLimit int `url:"member_limit,omitempty"`
+	// True, if users joining the chat via the link need to be approved by chat administrators.
+	// If True, MemberLimit can't be specified.
+	CreatesJoinRequest bool `url:"creates_join_request,omitempty"`
+}
+
+func (o *InviteLinkOptions) body(chatID ChatID) *httpf.Form {
+	if o == nil {
+		o = new(InviteLinkOptions)
+	}
+
+	return httpf.FormValue(o).Set("chat_id", chatID.queryParam())
+}
+
+// ExportChatInviteLink is used to generate a new primary invite link for a chat.
+// Any previously generated primary link is revoked.
+// Returns the new invite link as String on success.
+// See https://core.telegram.org/bots/api#exportchatinvitelink
+func (c *floodControlAware) ExportChatInviteLink(ctx context.Context, chatID ChatID) (string, error) {
+	body := new(httpf.Form).
+		Set("chat_id", chatID.queryParam())
+	var inviteLink string
+	return inviteLink, c.executeChat(ctx, chatID, "exportChatInviteLink", body, &inviteLink)
+}
+
+// CreateChatInviteLink is used to create an additional invite link for a chat.
+// Returns the new invite link as ChatInviteLink object.
+// See https://core.telegram.org/bots/api#createchatinvitelink
+func (c *floodControlAware) CreateChatInviteLink(ctx context.Context, chatID ChatID, options *InviteLinkOptions) (*ChatInviteLink, error) {
+	link := new(ChatInviteLink)
+	return link, c.executeChat(ctx, chatID, "createChatInviteLink", options.body(chatID), link)
+}
+
+// EditChatInviteLink is used to edit a non-primary invite link created by the bot.
+// Returns the edited invite link as a ChatInviteLink object.
+// See https://core.telegram.org/bots/api#editchatinvitelink
+func (c *floodControlAware) EditChatInviteLink(ctx context.Context, chatID ChatID, inviteLink string, options *InviteLinkOptions) (*ChatInviteLink, error) {
+	body := options.body(chatID).Set("invite_link", inviteLink)
+	link := new(ChatInviteLink)
+	return link, c.executeChat(ctx, chatID, "editChatInviteLink", body, link)
+}
+
+// RevokeChatInviteLink is used to revoke an invite link created by the bot.
+// If the primary link is revoked, a new link is automatically generated.
+// Returns the revoked invite link as ChatInviteLink object.
+// See https://core.telegram.org/bots/api#revokechatinvitelink
+func (c *floodControlAware) RevokeChatInviteLink(ctx context.Context, chatID ChatID, inviteLink string) (*ChatInviteLink, error) {
+	body := new(httpf.Form).
+		Set("chat_id", chatID.queryParam()).
+		Set("invite_link", inviteLink)
+	link := new(ChatInviteLink)
+	return link, c.executeChat(ctx, chatID, "revokeChatInviteLink", body, link)
+}
+
+// ApproveChatJoinRequest is used to approve a chat join request.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#approvechatjoinrequest
+func (c *floodControlAware) ApproveChatJoinRequest(ctx context.Context, chatID ChatID, userID ID) error {
+	return c.answerChatJoinRequest(ctx, "approveChatJoinRequest", chatID, userID)
+}
+
+// DeclineChatJoinRequest is used to decline a chat join request.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#declinechatjoinrequest
+func (c *floodControlAware) DeclineChatJoinRequest(ctx context.Context, chatID ChatID, userID ID) error {
+	return c.answerChatJoinRequest(ctx, "declineChatJoinRequest", chatID, userID)
+}
+
+func (c *floodControlAware) answerChatJoinRequest(ctx context.Context, method string, chatID ChatID, userID ID) error {
+	body := new(httpf.Form).
+		Set("chat_id", chatID.queryParam()).
+		Set("user_id", userID.queryParam())
+	var ok bool
+	if err := c.executeChat(ctx, chatID, method, body, &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// JoinRequestListener receives chat join requests.
+// ApproveChatJoinRequest or DeclineChatJoinRequest should be called to process the request.
+type JoinRequestListener interface {
+	OnJoinRequest(ctx context.Context, client Client, request *ChatJoinRequest) error
+}
+
+type JoinRequestListenerFunc func(context.Context, Client, *ChatJoinRequest) error
+
+func (fun JoinRequestListenerFunc) OnJoinRequest(ctx context.Context, client Client, request *ChatJoinRequest) error {
+	return fun(ctx, client, request)
+}
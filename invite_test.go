@@ -0,0 +1,71 @@
+package telegram_test
+
+import (
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInviteLinkOptions_Body(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	for _, method := range []string{"createChatInviteLink", "editChatInviteLink"} {
+		f.Server.Handle(method, func(call *telegramtest.Call) (interface{}, error) {
+			return telegram.ChatInviteLink{InviteLink: "https://t.me/+link", Name: call.Param("name")}, nil
+		})
+	}
+
+	ctx := timeout(t)
+	chatID := telegram.ID(-100)
+	link, err := f.Bot.CreateChatInviteLink(ctx, chatID, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://t.me/+link", link.InviteLink)
+
+	link, err = f.Bot.CreateChatInviteLink(ctx, chatID, &telegram.InviteLinkOptions{
+		Name:        "limited",
+		ExpireDate:  1700000000,
+		MemberLimit: 10,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "limited", link.Name)
+
+	_, err = f.Bot.EditChatInviteLink(ctx, chatID, "https://t.me/+link", &telegram.InviteLinkOptions{
+		Name:               "approval",
+		CreatesJoinRequest: true,
+	})
+	assert.Nil(t, err)
+
+	calls := f.Server.Calls("createChatInviteLink", "editChatInviteLink")
+	if assert.Len(t, calls, 3) {
+		assert.Equal(t, map[string]string{"chat_id": "-100"}, calls[0].Params)
+		assert.Equal(t, map[string]string{
+			"chat_id":      "-100",
+			"name":         "limited",
+			"expire_date":  "1700000000",
+			"member_limit": "10",
+		}, calls[1].Params)
+		assert.Equal(t, map[string]string{
+			"chat_id":              "-100",
+			"invite_link":          "https://t.me/+link",
+			"name":                 "approval",
+			"creates_join_request": "true",
+		}, calls[2].Params)
+	}
+}
+
+func TestBot_AnswerChatJoinRequest(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	ctx := timeout(t)
+	assert.Nil(t, f.Bot.ApproveChatJoinRequest(ctx, telegram.ID(-100), 2))
+	assert.Nil(t, f.Bot.DeclineChatJoinRequest(ctx, telegram.ID(-100), 3))
+
+	calls := f.Server.Calls("approveChatJoinRequest", "declineChatJoinRequest")
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, "approveChatJoinRequest", calls[0].Method)
+		assert.Equal(t, map[string]string{"chat_id": "-100", "user_id": "2"}, calls[0].Params)
+		assert.Equal(t, "declineChatJoinRequest", calls[1].Method)
+		assert.Equal(t, map[string]string{"chat_id": "-100", "user_id": "3"}, calls[1].Params)
+	}
+}
@@ -0,0 +1,102 @@
+package telegram
+
+import "encoding/json"
+
+type (
+	// KeyboardButtonPollType (https://core.telegram.org/bots/api#keyboardbuttonpolltype)
+	KeyboardButtonPollType struct {
+		// If QuizPoll is passed, the user will be allowed to create only polls in the quiz mode.
+		// If RegularPoll is passed, only regular polls will be allowed.
+		// Otherwise, the user will be allowed to create a poll of any type.
+		Type PollType `json:"type,omitempty"`
+	}
+
+	// KeyboardButtonRequestUsers (https://core.telegram.org/bots/api#keyboardbuttonrequestusers)
+	KeyboardButtonRequestUsers struct {
+		RequestID       int32 `json:"request_id"`
+		UserIsBot       *bool `json:"user_is_bot,omitempty"`
+		UserIsPremium   *bool `json:"user_is_premium,omitempty"`
+		MaxQuantity     int   `json:"max_quantity,omitempty"`
+		RequestName     bool  `json:"request_name,omitempty"`
+		RequestUsername bool  `json:"request_username,omitempty"`
+		RequestPhoto    bool  `json:"request_photo,omitempty"`
+	}
+
+	// KeyboardButtonRequestChat (https://core.telegram.org/bots/api#keyboardbuttonrequestchat)
+	KeyboardButtonRequestChat struct {
+		RequestID               int32                    `json:"request_id"`
+		ChatIsChannel           bool                     `json:"chat_is_channel"`
+		ChatIsForum             *bool                    `json:"chat_is_forum,omitempty"`
+		ChatHasUsername         *bool                    `json:"chat_has_username,omitempty"`
+		ChatIsCreated           bool                     `json:"chat_is_created,omitempty"`
+		UserAdministratorRights *ChatAdministratorRights `json:"user_administrator_rights,omitempty"`
+		BotAdministratorRights  *ChatAdministratorRights `json:"bot_administrator_rights,omitempty"`
+		BotIsMember             bool                     `json:"bot_is_member,omitempty"`
+		RequestTitle            bool                     `json:"request_title,omitempty"`
+		RequestUsername         bool                     `json:"request_username,omitempty"`
+		RequestPhoto            bool                     `json:"request_photo,omitempty"`
+	}
+
+	// WebAppInfo (https://core.telegram.org/bots/api#webappinfo)
+	WebAppInfo struct {
+		URL string `json:"url"`
+	}
+
+	// KeyboardButton (https://core.telegram.org/bots/api#keyboardbutton)
+	// At most one of the optional fields must be used to specify type of the button.
+	// If none are set, Text will be sent as a message when the button is pressed.
+	KeyboardButton struct {
+		Text            string                      `json:"text"`
+		RequestUsers    *KeyboardButtonRequestUsers `json:"request_users,omitempty"`
+		RequestChat     *KeyboardButtonRequestChat  `json:"request_chat,omitempty"`
+		RequestContact  bool                        `json:"request_contact,omitempty"`
+		RequestLocation bool                        `json:"request_location,omitempty"`
+		RequestPoll     *KeyboardButtonPollType     `json:"request_poll,omitempty"`
+		WebApp          *WebAppInfo                 `json:"web_app,omitempty"`
+	}
+
+	// ReplyKeyboardMarkup (https://core.telegram.org/bots/api#replykeyboardmarkup)
+	ReplyKeyboardMarkup struct {
+		Keyboard              [][]KeyboardButton `json:"keyboard"`
+		IsPersistent          bool               `json:"is_persistent,omitempty"`
+		ResizeKeyboard        bool               `json:"resize_keyboard,omitempty"`
+		OneTimeKeyboard       bool               `json:"one_time_keyboard,omitempty"`
+		InputFieldPlaceholder string             `json:"input_field_placeholder,omitempty"`
+		Selective             bool               `json:"selective,omitempty"`
+	}
+
+	// ReplyKeyboardRemove (https://core.telegram.org/bots/api#replykeyboardremove)
+	// RemoveKeyboard is always sent as true.
+	ReplyKeyboardRemove struct {
+		Selective bool `json:"selective,omitempty"`
+	}
+)
+
+// ReplyKeyboard creates a ReplyKeyboardMarkup with text-only buttons.
+// Use ReplyKeyboardMarkup directly in order to set other keyboard options.
+func ReplyKeyboard(rows ...[]string) ReplyMarkup {
+	keyboard := make([][]KeyboardButton, len(rows))
+	for i, row := range rows {
+		keyboard[i] = make([]KeyboardButton, len(row))
+		for j, text := range row {
+			keyboard[i][j] = KeyboardButton{Text: text}
+		}
+	}
+
+	return &ReplyKeyboardMarkup{Keyboard: keyboard}
+}
+
+func (m ReplyKeyboardMarkup) self() ReplyMarkup {
+	return m
+}
+
+func (r ReplyKeyboardRemove) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RemoveKeyboard bool `json:"remove_keyboard"`
+		Selective      bool `json:"selective,omitempty"`
+	}{true, r.Selective})
+}
+
+func (r ReplyKeyboardRemove) self() ReplyMarkup {
+	return r
+}
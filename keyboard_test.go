@@ -0,0 +1,87 @@
+package telegram_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ telegram.ReplyMarkup = telegram.ReplyKeyboardMarkup{}
+	_ telegram.ReplyMarkup = (*telegram.ReplyKeyboardMarkup)(nil)
+	_ telegram.ReplyMarkup = telegram.ReplyKeyboardRemove{}
+	_ telegram.ReplyMarkup = telegram.ForceReply{}
+	_ telegram.ReplyMarkup = telegram.InlineKeyboardMarkup{}
+	_ telegram.ReplyMarkup = telegram.ReplyKeyboard()
+	_ telegram.ReplyMarkup = telegram.InlineKeyboard()
+)
+
+func TestReplyMarkup_JSON(t *testing.T) {
+	isForum := true
+	for _, tc := range []struct {
+		name   string
+		markup telegram.ReplyMarkup
+		json   string
+	}{
+		{
+			name:   "reply keyboard",
+			markup: telegram.ReplyKeyboard([]string{"a", "b"}, []string{"c"}),
+			json:   `{"keyboard":[[{"text":"a"},{"text":"b"}],[{"text":"c"}]]}`,
+		},
+		{
+			name: "reply keyboard options",
+			markup: telegram.ReplyKeyboardMarkup{
+				Keyboard: [][]telegram.KeyboardButton{{
+					{Text: "contact", RequestContact: true},
+					{Text: "location", RequestLocation: true},
+					{Text: "poll", RequestPoll: &telegram.KeyboardButtonPollType{Type: telegram.QuizPoll}},
+					{Text: "users", RequestUsers: &telegram.KeyboardButtonRequestUsers{RequestID: 1, MaxQuantity: 2}},
+					{Text: "chat", RequestChat: &telegram.KeyboardButtonRequestChat{RequestID: 2, ChatIsForum: &isForum}},
+					{Text: "app", WebApp: &telegram.WebAppInfo{URL: "https://example.com"}},
+				}},
+				IsPersistent:          true,
+				ResizeKeyboard:        true,
+				OneTimeKeyboard:       true,
+				InputFieldPlaceholder: "choose",
+				Selective:             true,
+			},
+			json: `{"keyboard":[[` +
+				`{"text":"contact","request_contact":true},` +
+				`{"text":"location","request_location":true},` +
+				`{"text":"poll","request_poll":{"type":"quiz"}},` +
+				`{"text":"users","request_users":{"request_id":1,"max_quantity":2}},` +
+				`{"text":"chat","request_chat":{"request_id":2,"chat_is_channel":false,"chat_is_forum":true}},` +
+				`{"text":"app","web_app":{"url":"https://example.com"}}]],` +
+				`"is_persistent":true,"resize_keyboard":true,"one_time_keyboard":true,` +
+				`"input_field_placeholder":"choose","selective":true}`,
+		},
+		{
+			name:   "remove keyboard",
+			markup: telegram.ReplyKeyboardRemove{},
+			json:   `{"remove_keyboard":true}`,
+		},
+		{
+			name:   "remove keyboard selective",
+			markup: telegram.ReplyKeyboardRemove{Selective: true},
+			json:   `{"remove_keyboard":true,"selective":true}`,
+		},
+		{
+			name:   "force reply",
+			markup: telegram.ForceReply{ForceReply: true, Selective: true},
+			json:   `{"force_reply":true,"selective":true}`,
+		},
+		{
+			name:   "inline keyboard",
+			markup: telegram.InlineKeyboard([]telegram.Button{{"text", "/cmd", "arg"}}),
+			json:   `{"inline_keyboard":[[{"text":"text","callback_data":"/cmd arg"}]]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.markup)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.json, string(data))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package telegram
+
+import "context"
+
+// ChatMemberUpdated (https://core.telegram.org/bots/api#chatmemberupdated)
+type ChatMemberUpdated struct {
+	Chat                    Chat            `json:"chat"`
+	From                    User            `json:"from"`
+	Date                    int             `json:"date"`
+	OldChatMember           ChatMember      `json:"old_chat_member"`
+	NewChatMember           ChatMember      `json:"new_chat_member"`
+	InviteLink              *ChatInviteLink `json:"invite_link"`
+	ViaJoinRequest          bool            `json:"via_join_request"`
+	ViaChatFolderInviteLink bool            `json:"via_chat_folder_invite_link"`
+}
+
+// MemberEvent describes a chat member status transition.
+type MemberEvent string
+
+const (
+	// MemberJoined is sent when a user joins the chat or is added to the chat.
+	MemberJoined MemberEvent = "joined"
+	// MemberLeft is sent when a user leaves the chat.
+	MemberLeft MemberEvent = "left"
+	// MemberKicked is sent when a user is banned in the chat.
+	MemberKicked MemberEvent = "kicked"
+	// MemberUnbanned is sent when a banned user is unbanned without being added back to the chat.
+	MemberUnbanned MemberEvent = "unbanned"
+	// MemberPromoted is sent when a user becomes an administrator.
+	MemberPromoted MemberEvent = "promoted"
+	// MemberDemoted is sent when a user is no longer an administrator.
+	MemberDemoted MemberEvent = "demoted"
+	// MemberRestricted is sent when a user is restricted in the chat.
+	MemberRestricted MemberEvent = "restricted"
+	// MemberUpdated is sent for other changes (e.g. updated administrator rights or restrictions).
+	MemberUpdated MemberEvent = "updated"
+	// BotBlocked is sent when the user blocks the bot in a private chat.
+	BotBlocked MemberEvent = "blocked"
+	// BotUnblocked is sent when the user unblocks the bot in a private chat.
+	BotUnblocked MemberEvent = "unblocked"
+)
+
+// Event returns the chat member status transition for this update.
+func (u *ChatMemberUpdated) Event() MemberEvent {
+	before, after := &u.OldChatMember, &u.NewChatMember
+	if u.Chat.Type == PrivateChat {
+		switch {
+		case after.Status == ChatMemberKicked:
+			return BotBlocked
+		case before.Status == ChatMemberKicked:
+			return BotUnblocked
+		}
+	}
+
+	wasInChat, isInChat := before.IsInChat(), after.IsInChat()
+	switch {
+	case !wasInChat && isInChat:
+		return MemberJoined
+	case after.Status == ChatMemberKicked && before.Status != ChatMemberKicked:
+		return MemberKicked
+	case wasInChat && !isInChat:
+		return MemberLeft
+	case before.Status == ChatMemberKicked && after.Status == ChatMemberLeft:
+		return MemberUnbanned
+	case !before.IsAdministrator() && after.IsAdministrator():
+		return MemberPromoted
+	case before.IsAdministrator() && !after.IsAdministrator():
+		return MemberDemoted
+	case before.Status != ChatMemberRestricted && after.Status == ChatMemberRestricted:
+		return MemberRestricted
+	default:
+		return MemberUpdated
+	}
+}
+
+// ChatMemberListener receives chat member status transitions.
+// The update relates to the bot itself if NewChatMember.User is the bot.
+type ChatMemberListener interface {
+	OnMemberEvent(ctx context.Context, client Client, event MemberEvent, update *ChatMemberUpdated) error
+}
+
+type ChatMemberListenerFunc func(context.Context, Client, MemberEvent, *ChatMemberUpdated) error
+
+func (fun ChatMemberListenerFunc) OnMemberEvent(ctx context.Context, client Client, event MemberEvent, update *ChatMemberUpdated) error {
+	return fun(ctx, client, event, update)
+}
@@ -0,0 +1,73 @@
+package telegram_test
+
+import (
+	"fmt"
+	"testing"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatMemberUpdated_Event(t *testing.T) {
+	var (
+		creator    = telegram.ChatMember{Status: telegram.ChatMemberCreator}
+		admin      = telegram.ChatMember{Status: telegram.ChatMemberAdministrator}
+		member     = telegram.ChatMember{Status: telegram.ChatMemberMember}
+		restricted = telegram.ChatMember{Status: telegram.ChatMemberRestricted, IsMember: true}
+		outside    = telegram.ChatMember{Status: telegram.ChatMemberRestricted}
+		left       = telegram.ChatMember{Status: telegram.ChatMemberLeft}
+		kicked     = telegram.ChatMember{Status: telegram.ChatMemberKicked}
+	)
+
+	name := func(m telegram.ChatMember) string {
+		if m.Status == telegram.ChatMemberRestricted && !m.IsMember {
+			return "restricted outside"
+		}
+
+		return string(m.Status)
+	}
+
+	for _, tc := range []struct {
+		chatType telegram.ChatType
+		old, new telegram.ChatMember
+		event    telegram.MemberEvent
+	}{
+		{telegram.PrivateChat, member, kicked, telegram.BotBlocked},
+		{telegram.PrivateChat, kicked, member, telegram.BotUnblocked},
+		{telegram.PrivateChat, left, member, telegram.MemberJoined},
+		{telegram.Supergroup, left, member, telegram.MemberJoined},
+		{telegram.Supergroup, kicked, member, telegram.MemberJoined},
+		{telegram.Supergroup, left, restricted, telegram.MemberJoined},
+		{telegram.Supergroup, left, admin, telegram.MemberJoined},
+		{telegram.Supergroup, member, left, telegram.MemberLeft},
+		{telegram.Supergroup, restricted, left, telegram.MemberLeft},
+		{telegram.Supergroup, restricted, outside, telegram.MemberLeft},
+		{telegram.Supergroup, member, kicked, telegram.MemberKicked},
+		{telegram.Supergroup, admin, kicked, telegram.MemberKicked},
+		{telegram.Supergroup, outside, kicked, telegram.MemberKicked},
+		{telegram.GroupChat, member, kicked, telegram.MemberKicked},
+		{telegram.Supergroup, kicked, left, telegram.MemberUnbanned},
+		{telegram.Supergroup, member, admin, telegram.MemberPromoted},
+		{telegram.Supergroup, admin, creator, telegram.MemberUpdated},
+		{telegram.Supergroup, member, creator, telegram.MemberPromoted},
+		{telegram.Supergroup, admin, member, telegram.MemberDemoted},
+		{telegram.Supergroup, admin, restricted, telegram.MemberDemoted},
+		{telegram.Supergroup, member, restricted, telegram.MemberRestricted},
+		{telegram.Supergroup, left, outside, telegram.MemberRestricted},
+		{telegram.Supergroup, restricted, member, telegram.MemberUpdated},
+		{telegram.Supergroup, restricted, restricted, telegram.MemberUpdated},
+		{telegram.Supergroup, admin, admin, telegram.MemberUpdated},
+		{telegram.Channel, left, admin, telegram.MemberJoined},
+		{telegram.Channel, admin, left, telegram.MemberLeft},
+	} {
+		t.Run(fmt.Sprintf("%s %s to %s", tc.chatType, name(tc.old), name(tc.new)), func(t *testing.T) {
+			update := &telegram.ChatMemberUpdated{
+				Chat:          telegram.Chat{ID: 1, Type: tc.chatType},
+				OldChatMember: tc.old,
+				NewChatMember: tc.new,
+			}
+
+			assert.Equal(t, tc.event, update.Event())
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package telegram
+
+// MessageKind is the content type of a message.
+// It matches the name of the Message field which holds the content.
+type MessageKind string
+
+const (
+	TextMessage                  MessageKind = "text"
+	AnimationMessage             MessageKind = "animation"
+	AudioMessage                 MessageKind = "audio"
+	DocumentMessage              MessageKind = "document"
+	PhotoMessage                 MessageKind = "photo"
+	StickerMessage               MessageKind = "sticker"
+	VideoMessage                 MessageKind = "video"
+	VideoNoteMessage             MessageKind = "video_note"
+	VoiceMessage                 MessageKind = "voice"
+	ContactMessage               MessageKind = "contact"
+	DiceMessage                  MessageKind = "dice"
+	PollMessage                  MessageKind = "poll"
+	VenueMessage                 MessageKind = "venue"
+	LocationMessage              MessageKind = "location"
+	NewChatMembersMessage        MessageKind = "new_chat_members"
+	LeftChatMemberMessage        MessageKind = "left_chat_member"
+	NewChatTitleMessage          MessageKind = "new_chat_title"
+	NewChatPhotoMessage          MessageKind = "new_chat_photo"
+	DeleteChatPhotoMessage       MessageKind = "delete_chat_photo"
+	GroupChatCreatedMessage      MessageKind = "group_chat_created"
+	SupergroupChatCreatedMessage MessageKind = "supergroup_chat_created"
+	ChannelChatCreatedMessage    MessageKind = "channel_chat_created"
+	AutoDeleteTimerMessage       MessageKind = "message_auto_delete_timer_changed"
+	MigrateToChatMessage         MessageKind = "migrate_to_chat_id"
+	MigrateFromChatMessage       MessageKind = "migrate_from_chat_id"
+	PinnedMessageMessage         MessageKind = "pinned_message"
+	SuccessfulPaymentMessage     MessageKind = "successful_payment"
+	UsersSharedMessage           MessageKind = "users_shared"
+	ChatSharedMessage            MessageKind = "chat_shared"
+	WebAppDataMessage            MessageKind = "web_app_data"
+	UnknownMessage               MessageKind = ""
+)
+
+// MessageOriginType can be either “user”, “hidden_user”, “chat” or “channel”.
+type MessageOriginType string
+
+const (
+	UserOrigin       MessageOriginType = "user"
+	HiddenUserOrigin MessageOriginType = "hidden_user"
+	ChatOrigin       MessageOriginType = "chat"
+	ChannelOrigin    MessageOriginType = "channel"
+)
+
+type (
+	// PhotoSize (https://core.telegram.org/bots/api#photosize)
+	PhotoSize struct {
+		ID       string `json:"file_id"`
+		UniqueID string `json:"file_unique_id"`
+		Width    int    `json:"width"`
+		Height   int    `json:"height"`
+		Size     int64  `json:"file_size"`
+	}
+
+	// AnimationFile (https://core.telegram.org/bots/api#animation)
+	AnimationFile struct {
+		ID        string     `json:"file_id"`
+		UniqueID  string     `json:"file_unique_id"`
+		Width     int        `json:"width"`
+		Height    int        `json:"height"`
+		Duration  int        `json:"duration"`
+		Thumbnail *PhotoSize `json:"thumbnail"`
+		FileName  string     `json:"file_name"`
+		MIMEType  string     `json:"mime_type"`
+		Size      int64      `json:"file_size"`
+	}
+
+	// AudioFile (https://core.telegram.org/bots/api#audio)
+	AudioFile struct {
+		ID        string     `json:"file_id"`
+		UniqueID  string     `json:"file_unique_id"`
+		Duration  int        `json:"duration"`
+		Performer string     `json:"performer"`
+		Title     string     `json:"title"`
+		FileName  string     `json:"file_name"`
+		MIMEType  string     `json:"mime_type"`
+		Size      int64      `json:"file_size"`
+		Thumbnail *PhotoSize `json:"thumbnail"`
+	}
+
+	// DocumentFile (https://core.telegram.org/bots/api#document)
+	DocumentFile struct {
+		ID        string     `json:"file_id"`
+		UniqueID  string     `json:"file_unique_id"`
+		Thumbnail *PhotoSize `json:"thumbnail"`
+		FileName  string     `json:"file_name"`
+		MIMEType  string     `json:"mime_type"`
+		Size      int64      `json:"file_size"`
+	}
+
+	// StickerFile (https://core.telegram.org/bots/api#sticker)
+	StickerFile struct {
+		ID         string     `json:"file_id"`
+		UniqueID   string     `json:"file_unique_id"`
+		Type       string     `json:"type"`
+		Width      int        `json:"width"`
+		Height     int        `json:"height"`
+		IsAnimated bool       `json:"is_animated"`
+		IsVideo    bool       `json:"is_video"`
+		Thumbnail  *PhotoSize `json:"thumbnail"`
+		Emoji      string     `json:"emoji"`
+		SetName    string     `json:"set_name"`
+		Size       int64      `json:"file_size"`
+	}
+
+	// VideoFile (https://core.telegram.org/bots/api#video)
+	VideoFile struct {
+		ID        string     `json:"file_id"`
+		UniqueID  string     `json:"file_unique_id"`
+		Width     int        `json:"width"`
+		Height    int        `json:"height"`
+		Duration  int        `json:"duration"`
+		Thumbnail *PhotoSize `json:"thumbnail"`
+		FileName  string     `json:"file_name"`
+		MIMEType  string     `json:"mime_type"`
+		Size      int64      `json:"file_size"`
+	}
+
+	// VideoNoteFile (https://core.telegram.org/bots/api#videonote)
+	VideoNoteFile struct {
+		ID        string     `json:"file_id"`
+		UniqueID  string     `json:"file_unique_id"`
+		Length    int        `json:"length"`
+		Duration  int        `json:"duration"`
+		Thumbnail *PhotoSize `json:"thumbnail"`
+		Size      int64      `json:"file_size"`
+	}
+
+	// VoiceFile (https://core.telegram.org/bots/api#voice)
+	VoiceFile struct {
+		ID       string `json:"file_id"`
+		UniqueID string `json:"file_unique_id"`
+		Duration int    `json:"duration"`
+		MIMEType string `json:"mime_type"`
+		Size     int64  `json:"file_size"`
+	}
+
+	// Contact (https://core.telegram.org/bots/api#contact)
+	Contact struct {
+		PhoneNumber string `json:"phone_number"`
+		FirstName   string `json:"first_name"`
+		LastName    string `json:"last_name"`
+		UserID      ID     `json:"user_id"`
+		VCard       string `json:"vcard"`
+	}
+
+	// Dice (https://core.telegram.org/bots/api#dice)
+	Dice struct {
+		Emoji string `json:"emoji"`
+		Value int    `json:"value"`
+	}
+
+	// Venue (https://core.telegram.org/bots/api#venue)
+	Venue struct {
+		Location        Location `json:"location"`
+		Title           string   `json:"title"`
+		Address         string   `json:"address"`
+		FoursquareID    string   `json:"foursquare_id"`
+		FoursquareType  string   `json:"foursquare_type"`
+		GooglePlaceID   string   `json:"google_place_id"`
+		GooglePlaceType string   `json:"google_place_type"`
+	}
+
+	// MessageOrigin (https://core.telegram.org/bots/api#messageorigin)
+	// Fields are set depending on Type.
+	MessageOrigin struct {
+		Type            MessageOriginType `json:"type"`
+		Date            int               `json:"date"`
+		SenderUser      *User             `json:"sender_user"`
+		SenderUserName  string            `json:"sender_user_name"`
+		SenderChat      *Chat             `json:"sender_chat"`
+		Chat            *Chat             `json:"chat"`
+		MessageID       ID                `json:"message_id"`
+		AuthorSignature string            `json:"author_signature"`
+	}
+
+	// MessageAutoDeleteTimerChanged (https://core.telegram.org/bots/api#messageautodeletetimerchanged)
+	MessageAutoDeleteTimerChanged struct {
+		MessageAutoDeleteTime int `json:"message_auto_delete_time"`
+	}
+
+	// SharedUser (https://core.telegram.org/bots/api#shareduser)
+	SharedUser struct {
+		UserID    ID          `json:"user_id"`
+		FirstName string      `json:"first_name"`
+		LastName  string      `json:"last_name"`
+		Username  *Username   `json:"username"`
+		Photo     []PhotoSize `json:"photo"`
+	}
+
+	// UsersShared (https://core.telegram.org/bots/api#usersshared)
+	UsersShared struct {
+		RequestID int32        `json:"request_id"`
+		Users     []SharedUser `json:"users"`
+	}
+
+	// ChatShared (https://core.telegram.org/bots/api#chatshared)
+	ChatShared struct {
+		RequestID int32       `json:"request_id"`
+		ChatID    ID          `json:"chat_id"`
+		Title     string      `json:"title"`
+		Username  *Username   `json:"username"`
+		Photo     []PhotoSize `json:"photo"`
+	}
+
+	// WebAppData (https://core.telegram.org/bots/api#webappdata)
+	WebAppData struct {
+		Data       string `json:"data"`
+		ButtonText string `json:"button_text"`
+	}
+
+	// Message (https://core.telegram.org/bots/api#message)
+	Message struct {
+		ID                  ID                    `json:"message_id"`
+		MessageThreadID     ID                    `json:"message_thread_id"`
+		From                User                  `json:"from"`
+		SenderChat          *Chat                 `json:"sender_chat"`
+		Date                int                   `json:"date"`
+		Chat                Chat                  `json:"chat"`
+		ForwardOrigin       *MessageOrigin        `json:"forward_origin"`
+		IsTopicMessage      bool                  `json:"is_topic_message"`
+		IsAutomaticForward  bool                  `json:"is_automatic_forward"`
+		ReplyToMessage      *Message              `json:"reply_to_message"`
+		ViaBot              *User                 `json:"via_bot"`
+		EditDate            int                   `json:"edit_date"`
+		HasProtectedContent bool                  `json:"has_protected_content"`
+		MediaGroupID        string                `json:"media_group_id"`
+		AuthorSignature     string                `json:"author_signature"`
+		Text                string                `json:"text"`
+		Entities            []MessageEntity       `json:"entities"`
+		Animation           *AnimationFile        `json:"animation"`
+		Audio               *AudioFile            `json:"audio"`
+		Document            *DocumentFile         `json:"document"`
+		Photo               []PhotoSize           `json:"photo"`
+		Sticker             *StickerFile          `json:"sticker"`
+		Video               *VideoFile            `json:"video"`
+		VideoNote           *VideoNoteFile        `json:"video_note"`
+		Voice               *VoiceFile            `json:"voice"`
+		Caption             string                `json:"caption"`
+		CaptionEntities     []MessageEntity       `json:"caption_entities"`
+		HasMediaSpoiler     bool                  `json:"has_media_spoiler"`
+		Contact             *Contact              `json:"contact"`
+		Dice                *Dice                 `json:"dice"`
+		Poll                *Poll                 `json:"poll"`
+		Venue               *Venue                `json:"venue"`
+		Location            *Location             `json:"location"`
+		ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup"`
+
+		// Service messages.
+		NewChatMembers                []User                         `json:"new_chat_members"`
+		LeftChatMember                *User                          `json:"left_chat_member"`
+		NewChatTitle                  string                         `json:"new_chat_title"`
+		NewChatPhoto                  []PhotoSize                    `json:"new_chat_photo"`
+		DeleteChatPhoto               bool                           `json:"delete_chat_photo"`
+		GroupChatCreated              bool                           `json:"group_chat_created"`
+		SupergroupChatCreated         bool                           `json:"supergroup_chat_created"`
+		ChannelChatCreated            bool                           `json:"channel_chat_created"`
+		MessageAutoDeleteTimerChanged *MessageAutoDeleteTimerChanged `json:"message_auto_delete_timer_changed"`
+		MigrateToChatID               ID                             `json:"migrate_to_chat_id"`
+		MigrateFromChatID             ID                             `json:"migrate_from_chat_id"`
+		PinnedMessage                 *Message                       `json:"pinned_message"`
+		SuccessfulPayment             *SuccessfulPayment             `json:"successful_payment"`
+		UsersShared                   *UsersShared                   `json:"users_shared"`
+		ChatShared                    *ChatShared                    `json:"chat_shared"`
+		ConnectedWebsite              string                         `json:"connected_website"`
+		WebAppData                    *WebAppData                    `json:"web_app_data"`
+	}
+)
+
+// Kind returns message content type.
+// Note that animation messages also have Document set and venue messages also have Location set,
+// in which case the more specific kind is returned.
+func (m *Message) Kind() MessageKind {
+	switch {
+	case m.Text != "":
+		return TextMessage
+	case m.Animation != nil:
+		return AnimationMessage
+	case m.Audio != nil:
+		return AudioMessage
+	case m.Document != nil:
+		return DocumentMessage
+	case len(m.Photo) > 0:
+		return PhotoMessage
+	case m.Sticker != nil:
+		return StickerMessage
+	case m.Video != nil:
+		return VideoMessage
+	case m.VideoNote != nil:
+		return VideoNoteMessage
+	case m.Voice != nil:
+		return VoiceMessage
+	case m.Contact != nil:
+		return ContactMessage
+	case m.Dice != nil:
+		return DiceMessage
+	case m.Poll != nil:
+		return PollMessage
+	case m.Venue != nil:
+		return VenueMessage
+	case m.Location != nil:
+		return LocationMessage
+	case len(m.NewChatMembers) > 0:
+		return NewChatMembersMessage
+	case m.LeftChatMember != nil:
+		return LeftChatMemberMessage
+	case m.NewChatTitle != "":
+		return NewChatTitleMessage
+	case len(m.NewChatPhoto) > 0:
+		return NewChatPhotoMessage
+	case m.DeleteChatPhoto:
+		return DeleteChatPhotoMessage
+	case m.GroupChatCreated:
+		return GroupChatCreatedMessage
+	case m.SupergroupChatCreated:
+		return SupergroupChatCreatedMessage
+	case m.ChannelChatCreated:
+		return ChannelChatCreatedMessage
+	case m.MessageAutoDeleteTimerChanged != nil:
+		return AutoDeleteTimerMessage
+	case m.MigrateToChatID != 0:
+		return MigrateToChatMessage
+	case m.MigrateFromChatID != 0:
+		return MigrateFromChatMessage
+	case m.PinnedMessage != nil:
+		return PinnedMessageMessage
+	case m.SuccessfulPayment != nil:
+		return SuccessfulPaymentMessage
+	case m.UsersShared != nil:
+		return UsersSharedMessage
+	case m.ChatShared != nil:
+		return ChatSharedMessage
+	case m.WebAppData != nil:
+		return WebAppDataMessage
+	default:
+		return UnknownMessage
+	}
+}
+
+// IsService checks if the message is a service message (i.e. it has no user content).
+func (m *Message) IsService() bool {
+	switch m.Kind() {
+	case TextMessage, AnimationMessage, AudioMessage, DocumentMessage, PhotoMessage, StickerMessage,
+		VideoMessage, VideoNoteMessage, VoiceMessage, ContactMessage, DiceMessage, PollMessage,
+		VenueMessage, LocationMessage, UnknownMessage:
+		return false
+	default:
+		return true
+	}
+}
+
+// LargestPhoto returns the largest available photo size.
+// nil is returned if the message contains no photo.
+func (m *Message) LargestPhoto() *PhotoSize {
+	var largest *PhotoSize
+	for i := range m.Photo {
+		photo := &m.Photo[i]
+		if largest == nil || photo.Width*photo.Height > largest.Width*largest.Height {
+			largest = photo
+		}
+	}
+
+	return largest
+}
@@ -0,0 +1,54 @@
+package telegram_test
+
+import (
+	"testing"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_Kind(t *testing.T) {
+	photo := []telegram.PhotoSize{{ID: "photo"}}
+	for _, tc := range []struct {
+		name    string
+		message telegram.Message
+		kind    telegram.MessageKind
+	}{
+		{"text", telegram.Message{Text: "hello"}, telegram.TextMessage},
+		{"animation", telegram.Message{Animation: &telegram.AnimationFile{}, Document: &telegram.DocumentFile{}}, telegram.AnimationMessage},
+		{"audio", telegram.Message{Audio: &telegram.AudioFile{}}, telegram.AudioMessage},
+		{"document", telegram.Message{Document: &telegram.DocumentFile{}}, telegram.DocumentMessage},
+		{"photo", telegram.Message{Photo: photo, Caption: "caption"}, telegram.PhotoMessage},
+		{"sticker", telegram.Message{Sticker: &telegram.StickerFile{}}, telegram.StickerMessage},
+		{"video", telegram.Message{Video: &telegram.VideoFile{}}, telegram.VideoMessage},
+		{"video note", telegram.Message{VideoNote: &telegram.VideoNoteFile{}}, telegram.VideoNoteMessage},
+		{"voice", telegram.Message{Voice: &telegram.VoiceFile{}}, telegram.VoiceMessage},
+		{"contact", telegram.Message{Contact: &telegram.Contact{}}, telegram.ContactMessage},
+		{"dice", telegram.Message{Dice: &telegram.Dice{}}, telegram.DiceMessage},
+		{"poll", telegram.Message{Poll: &telegram.Poll{}}, telegram.PollMessage},
+		{"venue", telegram.Message{Venue: &telegram.Venue{}, Location: &telegram.Location{}}, telegram.VenueMessage},
+		{"location", telegram.Message{Location: &telegram.Location{}}, telegram.LocationMessage},
+		{"new chat members", telegram.Message{NewChatMembers: []telegram.User{{ID: 1}}}, telegram.NewChatMembersMessage},
+		{"left chat member", telegram.Message{LeftChatMember: &telegram.User{ID: 1}}, telegram.LeftChatMemberMessage},
+		{"new chat title", telegram.Message{NewChatTitle: "title"}, telegram.NewChatTitleMessage},
+		{"new chat photo", telegram.Message{NewChatPhoto: photo}, telegram.NewChatPhotoMessage},
+		{"delete chat photo", telegram.Message{DeleteChatPhoto: true}, telegram.DeleteChatPhotoMessage},
+		{"group chat created", telegram.Message{GroupChatCreated: true}, telegram.GroupChatCreatedMessage},
+		{"supergroup chat created", telegram.Message{SupergroupChatCreated: true}, telegram.SupergroupChatCreatedMessage},
+		{"channel chat created", telegram.Message{ChannelChatCreated: true}, telegram.ChannelChatCreatedMessage},
+		{"auto delete timer", telegram.Message{MessageAutoDeleteTimerChanged: &telegram.MessageAutoDeleteTimerChanged{}}, telegram.AutoDeleteTimerMessage},
+		{"migrate to chat", telegram.Message{MigrateToChatID: -100}, telegram.MigrateToChatMessage},
+		{"migrate from chat", telegram.Message{MigrateFromChatID: -10}, telegram.MigrateFromChatMessage},
+		{"pinned message", telegram.Message{PinnedMessage: &telegram.Message{Text: "pinned"}}, telegram.PinnedMessageMessage},
+		{"successful payment", telegram.Message{SuccessfulPayment: &telegram.SuccessfulPayment{}}, telegram.SuccessfulPaymentMessage},
+		{"users shared", telegram.Message{UsersShared: &telegram.UsersShared{}}, telegram.UsersSharedMessage},
+		{"chat shared", telegram.Message{ChatShared: &telegram.ChatShared{}}, telegram.ChatSharedMessage},
+		{"web app data", telegram.Message{WebAppData: &telegram.WebAppData{}}, telegram.WebAppDataMessage},
+		{"empty", telegram.Message{}, telegram.UnknownMessage},
+		{"unknown", telegram.Message{ConnectedWebsite: "example.com"}, telegram.UnknownMessage},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.kind, tc.message.Kind())
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jfk9w-go/flu/me3x"
+	"github.com/jfk9w-go/flu/syncf"
+	"github.com/pkg/errors"
+)
+
+// metrics reports Bot metrics to a me3x.Registry.
+// All methods are no-op if the registry is not set.
+type metrics struct {
+	registry me3x.Registry
+}
+
+// interceptor returns an Interceptor counting API calls by method and error code
+// and observing their latencies by method.
+func (m metrics) interceptor() Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		err := next(ctx, call)
+		labels := me3x.Labels{}.Add("method", call.Method)
+		m.registry.Counter("api_calls_total", labels.Add("code", errorCode(err))).Inc()
+		m.registry.Histogram("api_call_duration_seconds", labels, nil).Observe(call.Duration.Seconds())
+		return err
+	}
+}
+
+// floodWait observes time spent waiting for flood control limits.
+func (m metrics) floodWait(chatType string, duration time.Duration) {
+	if m.registry == nil {
+		return
+	}
+
+	labels := me3x.Labels{}.Add("chat_type", chatType)
+	m.registry.Histogram("flood_wait_seconds", labels, nil).Observe(duration.Seconds())
+}
+
+// retryAfter counts TooManyMessages errors.
+func (m metrics) retryAfter(chatType string) {
+	if m.registry == nil {
+		return
+	}
+
+	labels := me3x.Labels{}.Add("chat_type", chatType)
+	m.registry.Counter("retry_after_total", labels).Inc()
+}
+
+// update counts received updates by type.
+func (m metrics) update(updateType string) {
+	if m.registry == nil {
+		return
+	}
+
+	labels := me3x.Labels{}.Add("type", updateType)
+	m.registry.Counter("updates_total", labels).Inc()
+}
+
+// command observes command handler duration and counts handler errors by command key.
+func (m metrics) command(key string, duration time.Duration, err error) {
+	if m.registry == nil {
+		return
+	}
+
+	labels := me3x.Labels{}.Add("key", key)
+	m.registry.Histogram("command_duration_seconds", labels, nil).Observe(duration.Seconds())
+	if err != nil && !syncf.IsContextRelated(err) {
+		m.registry.Counter("command_errors_total", labels).Inc()
+	}
+}
+
+// errorCode returns the metric label value for the API call error.
+// Wrapped errors are unwrapped with errors.As.
+func errorCode(err error) string {
+	var (
+		apiErr          Error
+		tooManyMessages TooManyMessages
+		chatMigrated    ChatMigrated
+	)
+
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.As(err, &apiErr):
+		return strconv.Itoa(apiErr.ErrorCode)
+	case errors.As(err, &tooManyMessages):
+		return "429"
+	case errors.As(err, &chatMigrated):
+		return "400"
+	case syncf.IsContextRelated(err):
+		return "canceled"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,20 @@
+package telegram
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "ok", errorCode(nil))
+	assert.Equal(t, "403", errorCode(errors.Wrap(Error{ErrorCode: 403}, "send")))
+	assert.Equal(t, "429", errorCode(errors.Wrap(TooManyMessages{RetryAfter: time.Second}, "send")))
+	assert.Equal(t, "400", errorCode(errors.Wrap(ChatMigrated{MigrateToChatID: -100}, "send")))
+	assert.Equal(t, "canceled", errorCode(errors.Wrap(context.Canceled, "send")))
+	assert.Equal(t, "error", errorCode(io.ErrUnexpectedEOF))
+}
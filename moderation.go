@@ -0,0 +1,317 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/jfk9w-go/flu/httpf"
+	"github.com/pkg/errors"
+)
+
+// ChatMemberStatus can be either “creator”, “administrator”, “member”, “restricted”, “left” or “kicked”.
+type ChatMemberStatus string
+
+const (
+	ChatMemberCreator       ChatMemberStatus = "creator"
+	ChatMemberAdministrator ChatMemberStatus = "administrator"
+	ChatMemberMember        ChatMemberStatus = "member"
+	ChatMemberRestricted    ChatMemberStatus = "restricted"
+	ChatMemberLeft          ChatMemberStatus = "left"
+	ChatMemberKicked        ChatMemberStatus = "kicked"
+)
+
+type (
+	// ChatPermissions (https://core.telegram.org/bots/api#chatpermissions)
+	ChatPermissions struct {
+		CanSendMessages       bool `json:"can_send_messages,omitempty"`
+		CanSendAudios         bool `json:"can_send_audios,omitempty"`
+		CanSendDocuments      bool `json:"can_send_documents,omitempty"`
+		CanSendPhotos         bool `json:"can_send_photos,omitempty"`
+		CanSendVideos         bool `json:"can_send_videos,omitempty"`
+		CanSendVideoNotes     bool `json:"can_send_video_notes,omitempty"`
+		CanSendVoiceNotes     bool `json:"can_send_voice_notes,omitempty"`
+		CanSendPolls          bool `json:"can_send_polls,omitempty"`
+		CanSendOtherMessages  bool `json:"can_send_other_messages,omitempty"`
+		CanAddWebPagePreviews bool `json:"can_add_web_page_previews,omitempty"`
+		CanChangeInfo         bool `json:"can_change_info,omitempty"`
+		CanInviteUsers        bool `json:"can_invite_users,omitempty"`
+		CanPinMessages        bool `json:"can_pin_messages,omitempty"`
+		CanManageTopics       bool `json:"can_manage_topics,omitempty"`
+	}
+
+	// ChatAdministratorRights (https://core.telegram.org/bots/api#chatadministratorrights)
+	ChatAdministratorRights struct {
+		IsAnonymous         bool `json:"is_anonymous,omitempty"`
+		CanManageChat       bool `json:"can_manage_chat,omitempty"`
+		CanDeleteMessages   bool `json:"can_delete_messages,omitempty"`
+		CanManageVideoChats bool `json:"can_manage_video_chats,omitempty"`
+		CanRestrictMembers  bool `json:"can_restrict_members,omitempty"`
+		CanPromoteMembers   bool `json:"can_promote_members,omitempty"`
+		CanChangeInfo       bool `json:"can_change_info,omitempty"`
+		CanInviteUsers      bool `json:"can_invite_users,omitempty"`
+		CanPostStories      bool `json:"can_post_stories,omitempty"`
+		CanEditStories      bool `json:"can_edit_stories,omitempty"`
+		CanDeleteStories    bool `json:"can_delete_stories,omitempty"`
+		CanPostMessages     bool `json:"can_post_messages,omitempty"`
+		CanEditMessages     bool `json:"can_edit_messages,omitempty"`
+		CanPinMessages      bool `json:"can_pin_messages,omitempty"`
+		CanManageTopics     bool `json:"can_manage_topics,omitempty"`
+	}
+)
+
+// IsInChat checks if the user is currently a member of the chat.
+func (m *ChatMember) IsInChat() bool {
+	switch m.Status {
+	case ChatMemberCreator, ChatMemberAdministrator, ChatMemberMember:
+		return true
+	case ChatMemberRestricted:
+		return m.IsMember
+	default:
+		return false
+	}
+}
+
+// IsAdministrator checks if the user is the chat owner or an administrator.
+func (m *ChatMember) IsAdministrator() bool {
+	return m.Status == ChatMemberCreator || m.Status == ChatMemberAdministrator
+}
+
+// Until returns the date when restrictions or ban will be lifted.
+// Zero time is returned if the user is restricted or banned forever (or not restricted at all).
+func (m *ChatMember) Until() time.Time {
+	if m.UntilDate == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(m.UntilDate, 0)
+}
+
+// Rights returns administrator rights.
+// The chat owner has all rights.
+func (m *ChatMember) Rights() ChatAdministratorRights {
+	switch m.Status {
+	case ChatMemberCreator:
+		return ChatAdministratorRights{
+			IsAnonymous:         m.IsAnonymous,
+			CanManageChat:       true,
+			CanDeleteMessages:   true,
+			CanManageVideoChats: true,
+			CanRestrictMembers:  true,
+			CanPromoteMembers:   true,
+			CanChangeInfo:       true,
+			CanInviteUsers:      true,
+			CanPostStories:      true,
+			CanEditStories:      true,
+			CanDeleteStories:    true,
+			CanPostMessages:     true,
+			CanEditMessages:     true,
+			CanPinMessages:      true,
+			CanManageTopics:     true,
+		}
+	case ChatMemberAdministrator:
+		return ChatAdministratorRights{
+			IsAnonymous:         m.IsAnonymous,
+			CanManageChat:       m.CanManageChat,
+			CanDeleteMessages:   m.CanDeleteMessages,
+			CanManageVideoChats: m.CanManageVideoChats,
+			CanRestrictMembers:  m.CanRestrictMembers,
+			CanPromoteMembers:   m.CanPromoteMembers,
+			CanChangeInfo:       m.CanChangeInfo,
+			CanInviteUsers:      m.CanInviteUsers,
+			CanPostStories:      m.CanPostStories,
+			CanEditStories:      m.CanEditStories,
+			CanDeleteStories:    m.CanDeleteStories,
+			CanPostMessages:     m.CanPostMessages,
+			CanEditMessages:     m.CanEditMessages,
+			CanPinMessages:      m.CanPinMessages,
+			CanManageTopics:     m.CanManageTopics,
+		}
+	default:
+		return ChatAdministratorRights{}
+	}
+}
+
+// Permissions returns permissions of a restricted user.
+// ok is false if the user is not restricted (in which case chat default permissions apply).
+func (m *ChatMember) Permissions() (permissions ChatPermissions, ok bool) {
+	if m.Status != ChatMemberRestricted {
+		return
+	}
+
+	return ChatPermissions{
+		CanSendMessages:       m.CanSendMessages,
+		CanSendAudios:         m.CanSendAudios,
+		CanSendDocuments:      m.CanSendDocuments,
+		CanSendPhotos:         m.CanSendPhotos,
+		CanSendVideos:         m.CanSendVideos,
+		CanSendVideoNotes:     m.CanSendVideoNotes,
+		CanSendVoiceNotes:     m.CanSendVoiceNotes,
+		CanSendPolls:          m.CanSendPolls,
+		CanSendOtherMessages:  m.CanSendOtherMessages,
+		CanAddWebPagePreviews: m.CanAddWebPagePreviews,
+		CanChangeInfo:         m.CanChangeInfo,
+		CanInviteUsers:        m.CanInviteUsers,
+		CanPinMessages:        m.CanPinMessages,
+		CanManageTopics:       m.CanManageTopics,
+	}, true
+}
+
+// BanOptions is /banChatMember request options.
+type BanOptions struct {
+	// Date when the user will be unbanned, unix time.
+	// If user is banned for more than 366 days or less than 30 seconds from the current time they are considered to be banned forever.
+	UntilDate int64 `url:"until_date,omitempty"`
+	// Pass true to delete all messages from the chat for the user that is being removed.
+	RevokeMessages bool `url:"revoke_messages,omitempty"`
+}
+
+func (o *BanOptions) body(chatID ChatID, userID ID) *httpf.Form {
+	if o == nil {
+		o = new(BanOptions)
+	}
+
+	return httpf.FormValue(o).
+		Set("chat_id", chatID.queryParam()).
+		Set("user_id", userID.queryParam())
+}
+
+// RestrictOptions is /restrictChatMember request options.
+type RestrictOptions struct {
+	// Pass true if chat permissions are set independently.
+	UseIndependentChatPermissions bool `json:"use_independent_chat_permissions,omitempty"`
+	// Date when restrictions will be lifted for the user, unix time.
+	UntilDate int64 `json:"until_date,omitempty"`
+}
+
+// BanChatMember is used to ban a user in a group, a supergroup or a channel.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#banchatmember
+func (c *floodControlAware) BanChatMember(ctx context.Context, chatID ChatID, userID ID, options *BanOptions) error {
+	var ok bool
+	if err := c.executeChat(ctx, chatID, "banChatMember", options.body(chatID, userID), &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// UnbanChatMember is used to unban a previously banned user in a supergroup or channel.
+// If onlyIfBanned is false, the user will be removed from the chat if they are a member.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#unbanchatmember
+func (c *floodControlAware) UnbanChatMember(ctx context.Context, chatID ChatID, userID ID, onlyIfBanned bool) error {
+	body := new(httpf.Form).
+		Set("chat_id", chatID.queryParam()).
+		Set("user_id", userID.queryParam())
+	if onlyIfBanned {
+		body = body.Set("only_if_banned", "true")
+	}
+
+	var ok bool
+	if err := c.executeChat(ctx, chatID, "unbanChatMember", body, &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// RestrictChatMember is used to restrict a user in a supergroup.
+// Pass all permissions to lift restrictions from a user.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#restrictchatmember
+func (c *floodControlAware) RestrictChatMember(ctx context.Context, chatID ChatID, userID ID, permissions ChatPermissions, options *RestrictOptions) error {
+	type request struct {
+		ChatID      string          `json:"chat_id"`
+		UserID      ID              `json:"user_id"`
+		Permissions ChatPermissions `json:"permissions"`
+		*RestrictOptions
+	}
+
+	req := request{chatID.queryParam(), userID, permissions, options}
+	var ok bool
+	if err := c.executeChat(ctx, chatID, "restrictChatMember", flu.JSON(req), &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// PromoteChatMember is used to promote or demote a user in a supergroup or a channel.
+// Pass empty rights to demote a user.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#promotechatmember
+func (c *floodControlAware) PromoteChatMember(ctx context.Context, chatID ChatID, userID ID, rights ChatAdministratorRights) error {
+	type request struct {
+		ChatID string `json:"chat_id"`
+		UserID ID     `json:"user_id"`
+		ChatAdministratorRights
+	}
+
+	req := request{chatID.queryParam(), userID, rights}
+	var ok bool
+	if err := c.executeChat(ctx, chatID, "promoteChatMember", flu.JSON(req), &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// SetChatPermissions is used to set default chat permissions for all members.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#setchatpermissions
+func (c *floodControlAware) SetChatPermissions(ctx context.Context, chatID ChatID, permissions ChatPermissions, useIndependentChatPermissions bool) error {
+	type request struct {
+		ChatID                        string          `json:"chat_id"`
+		Permissions                   ChatPermissions `json:"permissions"`
+		UseIndependentChatPermissions bool            `json:"use_independent_chat_permissions,omitempty"`
+	}
+
+	req := request{chatID.queryParam(), permissions, useIndependentChatPermissions}
+	var ok bool
+	if err := c.executeChat(ctx, chatID, "setChatPermissions", flu.JSON(req), &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// SetChatAdministratorCustomTitle is used to set a custom title for an administrator in a supergroup promoted by the bot.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#setchatadministratorcustomtitle
+func (c *floodControlAware) SetChatAdministratorCustomTitle(ctx context.Context, chatID ChatID, userID ID, customTitle string) error {
+	body := new(httpf.Form).
+		Set("chat_id", chatID.queryParam()).
+		Set("user_id", userID.queryParam()).
+		Set("custom_title", customTitle)
+	var ok bool
+	if err := c.executeChat(ctx, chatID, "setChatAdministratorCustomTitle", body, &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package telegram_test
+
+import (
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBot_BanChatMember(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	ctx := timeout(t)
+	chatID := telegram.ID(-100)
+	assert.Nil(t, f.Bot.BanChatMember(ctx, chatID, 2, nil))
+	assert.Nil(t, f.Bot.BanChatMember(ctx, chatID, 3, &telegram.BanOptions{UntilDate: 1700000000, RevokeMessages: true}))
+	assert.Nil(t, f.Bot.UnbanChatMember(ctx, chatID, 2, true))
+
+	calls := f.Server.Calls("banChatMember", "unbanChatMember")
+	if assert.Len(t, calls, 3) {
+		assert.Equal(t, map[string]string{"chat_id": "-100", "user_id": "2"}, calls[0].Params)
+		assert.Equal(t, map[string]string{
+			"chat_id":         "-100",
+			"user_id":         "3",
+			"until_date":      "1700000000",
+			"revoke_messages": "true",
+		}, calls[1].Params)
+		assert.Equal(t, map[string]string{"chat_id": "-100", "user_id": "2", "only_if_banned": "true"}, calls[2].Params)
+	}
+}
+
+func TestBot_RestrictChatMember(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	ctx := timeout(t)
+	chatID := telegram.ID(-100)
+	assert.Nil(t, f.Bot.RestrictChatMember(ctx, chatID, 2, telegram.ChatPermissions{}, nil))
+	assert.Nil(t, f.Bot.RestrictChatMember(ctx, chatID, 2, telegram.ChatPermissions{
+		CanSendMessages: true,
+		CanSendPhotos:   true,
+		CanSendPolls:    true,
+	}, &telegram.RestrictOptions{UseIndependentChatPermissions: true, UntilDate: 1700000000}))
+
+	calls := f.Server.Calls("restrictChatMember")
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, "-100", calls[0].Param("chat_id"))
+		assert.Equal(t, "2", calls[0].Param("user_id"))
+		assert.JSONEq(t, `{}`, calls[0].Param("permissions"))
+		for _, key := range []string{"use_independent_chat_permissions", "until_date"} {
+			_, ok := calls[0].Params[key]
+			assert.False(t, ok, key)
+		}
+
+		assert.JSONEq(t, `{"can_send_messages":true,"can_send_photos":true,"can_send_polls":true}`, calls[1].Param("permissions"))
+		assert.Equal(t, "true", calls[1].Param("use_independent_chat_permissions"))
+		assert.Equal(t, "1700000000", calls[1].Param("until_date"))
+	}
+}
+
+func TestBot_PromoteChatMember(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	ctx := timeout(t)
+	chatID := telegram.ID(-100)
+	assert.Nil(t, f.Bot.PromoteChatMember(ctx, chatID, 2, telegram.ChatAdministratorRights{
+		CanDeleteMessages:  true,
+		CanRestrictMembers: true,
+	}))
+	assert.Nil(t, f.Bot.SetChatPermissions(ctx, chatID, telegram.ChatPermissions{CanSendMessages: true}, true))
+
+	calls := f.Server.Calls("promoteChatMember", "setChatPermissions")
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, map[string]string{
+			"chat_id":              "-100",
+			"user_id":              "2",
+			"can_delete_messages":  "true",
+			"can_restrict_members": "true",
+		}, calls[0].Params)
+
+		assert.JSONEq(t, `{"can_send_messages":true}`, calls[1].Param("permissions"))
+		assert.Equal(t, "true", calls[1].Param("use_independent_chat_permissions"))
+	}
+}
@@ -29,6 +29,46 @@ type GetUpdatesOptions struct {
 	AllowedUpdates []string `json:"allowed_updates,omitempty"`
 }
 
+// WebhookOptions is /setWebhook request options.
+// See https://core.telegram.org/bots/api#setwebhook
+type WebhookOptions struct {
+	// Public key certificate so that the root certificate in use can be checked.
+	Certificate flu.Input `url:"-"`
+	// The fixed IP address which will be used to send webhook requests instead of the IP address resolved through DNS.
+	IPAddress string `url:"ip_address,omitempty"`
+	// The maximum allowed number of simultaneous HTTPS connections to the webhook for update delivery, 1-100.
+	// Defaults to 40.
+	MaxConnections int `url:"max_connections,omitempty"`
+	// List the types of updates you want your bot to receive.
+	AllowedUpdates []string `url:"-"`
+	// Pass true to drop all pending updates.
+	DropPendingUpdates bool `url:"drop_pending_updates,omitempty"`
+	// A secret token to be sent in a header “X-Telegram-Bot-Api-Secret-Token” in every webhook request.
+	SecretToken string `url:"secret_token,omitempty"`
+}
+
+func (o *WebhookOptions) body(url string) (flu.EncoderTo, error) {
+	if o == nil {
+		o = new(WebhookOptions)
+	}
+
+	form := httpf.FormValue(o).Set("url", url)
+	if o.AllowedUpdates != nil {
+		bytes, err := json.Marshal(o.AllowedUpdates)
+		if err != nil {
+			return nil, errors.Wrap(err, "serialize allowed_updates")
+		}
+
+		form = form.Set("allowed_updates", string(bytes))
+	}
+
+	if o.Certificate != nil {
+		return form.Multipart().File("certificate", "certificate.pem", o.Certificate), nil
+	}
+
+	return form, nil
+}
+
 type SendOptions struct {
 	DisableNotification bool
 	ReplyToMessageID    ID
@@ -0,0 +1,228 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/jfk9w-go/flu/httpf"
+	"github.com/pkg/errors"
+)
+
+// StarsCurrency is the currency code for payments in Telegram Stars.
+// provider_token should be empty for Telegram Stars payments.
+// See https://core.telegram.org/bots/payments-stars
+const StarsCurrency = "XTR"
+
+type (
+	// LabeledPrice (https://core.telegram.org/bots/api#labeledprice)
+	LabeledPrice struct {
+		Label  string `json:"label"`
+		Amount int    `json:"amount"`
+	}
+
+	// ShippingAddress (https://core.telegram.org/bots/api#shippingaddress)
+	ShippingAddress struct {
+		CountryCode string `json:"country_code"`
+		State       string `json:"state"`
+		City        string `json:"city"`
+		StreetLine1 string `json:"street_line1"`
+		StreetLine2 string `json:"street_line2"`
+		PostCode    string `json:"post_code"`
+	}
+
+	// OrderInfo (https://core.telegram.org/bots/api#orderinfo)
+	OrderInfo struct {
+		Name            string           `json:"name"`
+		PhoneNumber     string           `json:"phone_number"`
+		Email           string           `json:"email"`
+		ShippingAddress *ShippingAddress `json:"shipping_address"`
+	}
+
+	// ShippingOption (https://core.telegram.org/bots/api#shippingoption)
+	ShippingOption struct {
+		ID     string         `json:"id"`
+		Title  string         `json:"title"`
+		Prices []LabeledPrice `json:"prices"`
+	}
+
+	// ShippingQuery (https://core.telegram.org/bots/api#shippingquery)
+	ShippingQuery struct {
+		ID              string          `json:"id"`
+		From            User            `json:"from"`
+		InvoicePayload  string          `json:"invoice_payload"`
+		ShippingAddress ShippingAddress `json:"shipping_address"`
+	}
+
+	// PreCheckoutQuery (https://core.telegram.org/bots/api#precheckoutquery)
+	PreCheckoutQuery struct {
+		ID               string     `json:"id"`
+		From             User       `json:"from"`
+		Currency         string     `json:"currency"`
+		TotalAmount      int        `json:"total_amount"`
+		InvoicePayload   string     `json:"invoice_payload"`
+		ShippingOptionID string     `json:"shipping_option_id"`
+		OrderInfo        *OrderInfo `json:"order_info"`
+	}
+
+	// SuccessfulPayment (https://core.telegram.org/bots/api#successfulpayment)
+	SuccessfulPayment struct {
+		Currency                string     `json:"currency"`
+		TotalAmount             int        `json:"total_amount"`
+		InvoicePayload          string     `json:"invoice_payload"`
+		ShippingOptionID        string     `json:"shipping_option_id"`
+		OrderInfo               *OrderInfo `json:"order_info"`
+		TelegramPaymentChargeID string     `json:"telegram_payment_charge_id"`
+		ProviderPaymentChargeID string     `json:"provider_payment_charge_id"`
+	}
+)
+
+// Invoice is used for sending invoices and creating invoice links.
+// See https://core.telegram.org/bots/api#sendinvoice
+type Invoice struct {
+	Title                     string         `url:"title"`
+	Description               string         `url:"description"`
+	Payload                   string         `url:"payload"`
+	ProviderToken             string         `url:"provider_token,omitempty"`
+	Currency                  string         `url:"currency"`
+	Prices                    []LabeledPrice `url:"-"`
+	MaxTipAmount              int            `url:"max_tip_amount,omitempty"`
+	SuggestedTipAmounts       []int          `url:"-"`
+	StartParameter            string         `url:"start_parameter,omitempty"`
+	ProviderData              string         `url:"provider_data,omitempty"`
+	PhotoURL                  string         `url:"photo_url,omitempty"`
+	PhotoSize                 int            `url:"photo_size,omitempty"`
+	PhotoWidth                int            `url:"photo_width,omitempty"`
+	PhotoHeight               int            `url:"photo_height,omitempty"`
+	NeedName                  bool           `url:"need_name,omitempty"`
+	NeedPhoneNumber           bool           `url:"need_phone_number,omitempty"`
+	NeedEmail                 bool           `url:"need_email,omitempty"`
+	NeedShippingAddress       bool           `url:"need_shipping_address,omitempty"`
+	SendPhoneNumberToProvider bool           `url:"send_phone_number_to_provider,omitempty"`
+	SendEmailToProvider       bool           `url:"send_email_to_provider,omitempty"`
+	IsFlexible                bool           `url:"is_flexible,omitempty"`
+}
+
+func (i Invoice) kind() string {
+	return "invoice"
+}
+
+func (i Invoice) body(form *httpf.Form) (flu.EncoderTo, error) {
+	prices, err := json.Marshal(i.Prices)
+	if err != nil {
+		return nil, errors.Wrap(err, "serialize prices")
+	}
+
+	form = form.Set("prices", string(prices))
+	if len(i.SuggestedTipAmounts) > 0 {
+		amounts, err := json.Marshal(i.SuggestedTipAmounts)
+		if err != nil {
+			return nil, errors.Wrap(err, "serialize suggested_tip_amounts")
+		}
+
+		form = form.Set("suggested_tip_amounts", string(amounts))
+	}
+
+	return form, nil
+}
+
+func (i Invoice) self() Sendable {
+	return i
+}
+
+// CreateInvoiceLink is used to create a link for an invoice.
+// Returns the created invoice link as String on success.
+// See https://core.telegram.org/bots/api#createinvoicelink
+func (c *baseClient) CreateInvoiceLink(ctx context.Context, invoice Invoice) (string, error) {
+	body, err := invoice.body(httpf.FormValue(invoice))
+	if err != nil {
+		return "", err
+	}
+
+	var link string
+	return link, c.Execute(ctx, "createInvoiceLink", body, &link)
+}
+
+// AnswerShippingQuery is used to reply to shipping queries.
+// If errorMessage is empty, the delivery to the specified address is considered possible,
+// and options must contain available shipping options.
+// On success, True is returned.
+// See https://core.telegram.org/bots/api#answershippingquery
+func (c *baseClient) AnswerShippingQuery(ctx context.Context, id string, options []ShippingOption, errorMessage string) error {
+	type request struct {
+		ShippingQueryID string           `json:"shipping_query_id"`
+		Ok              bool             `json:"ok"`
+		ShippingOptions []ShippingOption `json:"shipping_options,omitempty"`
+		ErrorMessage    string           `json:"error_message,omitempty"`
+	}
+
+	req := request{id, errorMessage == "", options, errorMessage}
+	var ok bool
+	if err := c.Execute(ctx, "answerShippingQuery", flu.JSON(req), &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// AnswerPreCheckoutQuery is used to respond to pre-checkout queries.
+// If errorMessage is empty, the bot is ready to proceed with the order.
+// The answer must be sent within 10 seconds after the pre-checkout query was sent.
+// On success, True is returned.
+// See https://core.telegram.org/bots/api#answerprecheckoutquery
+func (c *baseClient) AnswerPreCheckoutQuery(ctx context.Context, id string, errorMessage string) error {
+	body := new(httpf.Form).
+		Set("pre_checkout_query_id", id)
+	if errorMessage == "" {
+		body = body.Set("ok", "true")
+	} else {
+		body = body.
+			Set("ok", "false").
+			Set("error_message", errorMessage)
+	}
+
+	var ok bool
+	if err := c.Execute(ctx, "answerPreCheckoutQuery", body, &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// RefundStarPayment refunds a successful payment in Telegram Stars.
+// Returns True on success.
+// See https://core.telegram.org/bots/api#refundstarpayment
+func (c *baseClient) RefundStarPayment(ctx context.Context, userID ID, telegramPaymentChargeID string) error {
+	body := new(httpf.Form).
+		Set("user_id", userID.queryParam()).
+		Set("telegram_payment_charge_id", telegramPaymentChargeID)
+	var ok bool
+	if err := c.Execute(ctx, "refundStarPayment", body, &ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("not ok")
+	}
+
+	return nil
+}
+
+// PaymentListener handles queries issued during the payment process.
+// See https://core.telegram.org/bots/payments#the-payments-api
+type PaymentListener interface {
+	// OnShippingQuery is called for invoices with flexible price.
+	// AnswerShippingQuery must be called.
+	OnShippingQuery(ctx context.Context, client Client, query *ShippingQuery) error
+	// OnPreCheckoutQuery is called before the payment is confirmed.
+	// AnswerPreCheckoutQuery must be called within 10 seconds.
+	OnPreCheckoutQuery(ctx context.Context, client Client, query *PreCheckoutQuery) error
+}
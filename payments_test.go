@@ -0,0 +1,83 @@
+package telegram_test
+
+import (
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoice_Body(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	ctx := timeout(t)
+	_, err := f.Bot.Send(ctx, f.Chat.ID, telegram.Invoice{
+		Title:         "Subscription",
+		Description:   "One month",
+		Payload:       "sub-1",
+		ProviderToken: "token",
+		Currency:      "USD",
+		Prices: []telegram.LabeledPrice{
+			{Label: "Subscription", Amount: 500},
+			{Label: "Discount", Amount: -100},
+		},
+		MaxTipAmount:        300,
+		SuggestedTipAmounts: []int{100, 200, 300},
+		NeedEmail:           true,
+	}, nil)
+	assert.Nil(t, err)
+
+	_, err = f.Bot.Send(ctx, f.Chat.ID, telegram.Invoice{
+		Title:       "Stars",
+		Description: "Pay with stars",
+		Payload:     "stars-1",
+		Currency:    telegram.StarsCurrency,
+		Prices:      []telegram.LabeledPrice{{Label: "Stars", Amount: 50}},
+	}, nil)
+	assert.Nil(t, err)
+
+	calls := f.Server.Calls("sendInvoice")
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, "1", calls[0].Param("chat_id"))
+		assert.Equal(t, "sub-1", calls[0].Param("payload"))
+		assert.Equal(t, "token", calls[0].Param("provider_token"))
+		assert.Equal(t, "USD", calls[0].Param("currency"))
+		assert.JSONEq(t, `[{"label":"Subscription","amount":500},{"label":"Discount","amount":-100}]`, calls[0].Param("prices"))
+		assert.Equal(t, "300", calls[0].Param("max_tip_amount"))
+		assert.JSONEq(t, `[100,200,300]`, calls[0].Param("suggested_tip_amounts"))
+		assert.Equal(t, "true", calls[0].Param("need_email"))
+
+		assert.Equal(t, telegram.StarsCurrency, calls[1].Param("currency"))
+		assert.JSONEq(t, `[{"label":"Stars","amount":50}]`, calls[1].Param("prices"))
+		for _, key := range []string{"provider_token", "max_tip_amount", "suggested_tip_amounts", "need_email"} {
+			_, ok := calls[1].Params[key]
+			assert.False(t, ok, key)
+		}
+	}
+}
+
+func TestBot_CreateInvoiceLink(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	f.Server.Handle("createInvoiceLink", func(call *telegramtest.Call) (interface{}, error) {
+		return "https://t.me/$invoice", nil
+	})
+
+	link, err := f.Bot.CreateInvoiceLink(timeout(t), telegram.Invoice{
+		Title:       "Stars",
+		Description: "Pay with stars",
+		Payload:     "stars-1",
+		Currency:    telegram.StarsCurrency,
+		Prices:      []telegram.LabeledPrice{{Label: "Stars", Amount: 50}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "https://t.me/$invoice", link)
+
+	calls := f.Server.Calls("createInvoiceLink")
+	if assert.Len(t, calls, 1) {
+		assert.Equal(t, "stars-1", calls[0].Param("payload"))
+		assert.JSONEq(t, `[{"label":"Stars","amount":50}]`, calls[0].Param("prices"))
+		_, ok := calls[0].Params["chat_id"]
+		assert.False(t, ok)
+	}
+}
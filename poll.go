@@ -0,0 +1,206 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/jfk9w-go/flu/httpf"
+	"github.com/pkg/errors"
+)
+
+// PollType is either “regular” or “quiz”.
+type PollType string
+
+const (
+	RegularPoll PollType = "regular"
+	QuizPoll    PollType = "quiz"
+)
+
+type (
+	// PollOption (https://core.telegram.org/bots/api#polloption)
+	PollOption struct {
+		Text       string `json:"text"`
+		VoterCount int    `json:"voter_count"`
+	}
+
+	// Poll (https://core.telegram.org/bots/api#poll)
+	Poll struct {
+		ID                    string          `json:"id"`
+		Question              string          `json:"question"`
+		Options               []PollOption    `json:"options"`
+		TotalVoterCount       int             `json:"total_voter_count"`
+		IsClosed              bool            `json:"is_closed"`
+		IsAnonymous           bool            `json:"is_anonymous"`
+		Type                  PollType        `json:"type"`
+		AllowsMultipleAnswers bool            `json:"allows_multiple_answers"`
+		CorrectOptionID       *int            `json:"correct_option_id"`
+		Explanation           string          `json:"explanation"`
+		ExplanationEntities   []MessageEntity `json:"explanation_entities"`
+		OpenPeriod            int             `json:"open_period"`
+		CloseDate             int             `json:"close_date"`
+	}
+
+	// PollAnswer (https://core.telegram.org/bots/api#pollanswer)
+	PollAnswer struct {
+		PollID    string `json:"poll_id"`
+		VoterChat *Chat  `json:"voter_chat"`
+		User      *User  `json:"user"`
+		OptionIDs []int  `json:"option_ids"`
+	}
+)
+
+// InputPoll is a poll or a quiz which can be sent with Send.
+// IsAnonymous is a pointer since polls are anonymous by default in Bot API:
+// leave it nil to keep the default or point it to false in order to send a non-anonymous poll.
+// Answers for non-anonymous polls are received as poll_answer updates.
+// See https://core.telegram.org/bots/api#sendpoll
+type InputPoll struct {
+	Question              string    `url:"question"`
+	Options               []string  `url:"-"`
+	IsAnonymous           *bool     `url:"is_anonymous,omitempty"`
+	Type                  PollType  `url:"type,omitempty"`
+	AllowsMultipleAnswers bool      `url:"allows_multiple_answers,omitempty"`
+	CorrectOptionID       int       `url:"-"`
+	Explanation           string    `url:"explanation,omitempty"`
+	ExplanationParseMode  ParseMode `url:"explanation_parse_mode,omitempty"`
+	OpenPeriod            int       `url:"open_period,omitempty"`
+	CloseDate             int       `url:"close_date,omitempty"`
+	IsClosed              bool      `url:"is_closed,omitempty"`
+}
+
+func (p InputPoll) kind() string {
+	return "poll"
+}
+
+func (p InputPoll) body(form *httpf.Form) (flu.EncoderTo, error) {
+	type option struct {
+		Text string `json:"text"`
+	}
+
+	options := make([]option, len(p.Options))
+	for i, text := range p.Options {
+		options[i] = option{text}
+	}
+
+	bytes, err := json.Marshal(options)
+	if err != nil {
+		return nil, errors.Wrap(err, "serialize options")
+	}
+
+	form = form.Set("options", string(bytes))
+	if p.Type == QuizPoll {
+		form = form.Set("correct_option_id", strconv.Itoa(p.CorrectOptionID))
+	}
+
+	return form, nil
+}
+
+func (p InputPoll) self() Sendable {
+	return p
+}
+
+// StopPoll is used to stop a poll which was sent by the bot.
+// On success, the stopped Poll is returned.
+// See https://core.telegram.org/bots/api#stoppoll
+func (c *floodControlAware) StopPoll(ctx context.Context, ref MessageRef, markup ReplyMarkup) (*Poll, error) {
+	form, err := setReplyMarkup(ref.editForm(new(httpf.Form)), markup)
+	if err != nil {
+		return nil, err
+	}
+
+	poll := new(Poll)
+	if err := c.execute(ctx, ref.ChatID, "stopPoll", form, poll); err != nil && err != errUnknownRecipient {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+// PollListener receives poll state and poll answer updates.
+type PollListener interface {
+	// OnPoll is called when poll state changes.
+	// Bots receive only updates about stopped polls and polls which are sent by the bot.
+	OnPoll(ctx context.Context, client Client, poll *Poll) error
+	// OnPollAnswer is called when a user changes their answer in a non-anonymous poll.
+	// Bots receive new votes only in polls that were sent by the bot itself.
+	OnPollAnswer(ctx context.Context, client Client, answer *PollAnswer) error
+}
+
+// PollTally is a PollListener which keeps track of votes in non-anonymous polls.
+type PollTally struct {
+	votes map[string]map[ID][]int
+	mu    sync.RWMutex
+}
+
+func (t *PollTally) OnPoll(ctx context.Context, client Client, poll *Poll) error {
+	return nil
+}
+
+func (t *PollTally) OnPollAnswer(ctx context.Context, client Client, answer *PollAnswer) error {
+	var voterID ID
+	switch {
+	case answer.User != nil:
+		voterID = answer.User.ID
+	case answer.VoterChat != nil:
+		voterID = answer.VoterChat.ID
+	default:
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.votes == nil {
+		t.votes = make(map[string]map[ID][]int)
+	}
+
+	votes, ok := t.votes[answer.PollID]
+	if !ok {
+		votes = make(map[ID][]int)
+		t.votes[answer.PollID] = votes
+	}
+
+	if len(answer.OptionIDs) == 0 {
+		// vote retracted
+		delete(votes, voterID)
+	} else {
+		votes[voterID] = answer.OptionIDs
+	}
+
+	return nil
+}
+
+// Votes returns the number of votes per option ID.
+func (t *PollTally) Votes(pollID string) map[int]int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	result := make(map[int]int)
+	for _, optionIDs := range t.votes[pollID] {
+		for _, optionID := range optionIDs {
+			result[optionID]++
+		}
+	}
+
+	return result
+}
+
+// Voters returns option IDs chosen by each voter (user or chat ID).
+func (t *PollTally) Voters(pollID string) map[ID][]int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	result := make(map[ID][]int, len(t.votes[pollID]))
+	for voterID, optionIDs := range t.votes[pollID] {
+		result[voterID] = optionIDs
+	}
+
+	return result
+}
+
+// Forget removes votes for the poll.
+func (t *PollTally) Forget(pollID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.votes, pollID)
+}
@@ -0,0 +1,30 @@
+package telegram_test
+
+import (
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputPoll_IsAnonymous(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	ctx := timeout(t)
+	message, err := f.Bot.Send(ctx, f.Chat.ID, telegram.InputPoll{Question: "default", Options: []string{"a", "b"}}, nil)
+	assert.Nil(t, err)
+	assert.True(t, message.Poll.IsAnonymous)
+
+	anonymous := false
+	message, err = f.Bot.Send(ctx, f.Chat.ID, telegram.InputPoll{Question: "public", Options: []string{"a", "b"}, IsAnonymous: &anonymous}, nil)
+	assert.Nil(t, err)
+	assert.False(t, message.Poll.IsAnonymous)
+
+	calls := f.Server.Calls("sendPoll")
+	if assert.Len(t, calls, 2) {
+		_, ok := calls[0].Params["is_anonymous"]
+		assert.False(t, ok)
+		assert.Equal(t, "false", calls[1].Param("is_anonymous"))
+	}
+}
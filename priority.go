@@ -0,0 +1,132 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Priority is a send priority.
+// Requests with higher priority pass the global rate limit ahead of requests with lower priority.
+type Priority int
+
+const (
+	// LowPriority should be used for bulk or background traffic (e.g. broadcasts).
+	LowPriority Priority = iota - 1
+	// NormalPriority is the default priority.
+	NormalPriority
+	// HighPriority should be used for interactive traffic (e.g. replies to commands).
+	// Handlers registered with CommandListener, UpdateListener and the listeners built on top of it
+	// (including DialogListener step timeouts) run with HighPriority.
+	HighPriority
+)
+
+// Priorities contains all priorities from the highest to the lowest.
+var Priorities = []Priority{HighPriority, NormalPriority, LowPriority}
+
+func (p Priority) index() int {
+	switch {
+	case p < LowPriority:
+		return 0
+	case p > HighPriority:
+		return int(HighPriority - LowPriority)
+	default:
+		return int(p - LowPriority)
+	}
+}
+
+type priorityKey struct{}
+
+// WithPriority sets the priority for requests made with the context.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+func contextPriority(ctx context.Context) Priority {
+	value, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok {
+		return NormalPriority
+	}
+
+	return value
+}
+
+// priorityQueue lets only one waiter through at a time,
+// picking the next one in priority order (FIFO within the same priority).
+type priorityQueue struct {
+	busy    bool
+	waiters [3][]chan struct{}
+	depth   [3]int64
+	mu      sync.Mutex
+}
+
+func (q *priorityQueue) acquire(ctx context.Context, priority Priority) error {
+	q.mu.Lock()
+	if !q.busy {
+		q.busy = true
+		q.mu.Unlock()
+		return nil
+	}
+
+	i := priority.index()
+	ready := make(chan struct{})
+	q.waiters[i] = append(q.waiters[i], ready)
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		removed := false
+		for j, waiter := range q.waiters[i] {
+			if waiter == ready {
+				q.waiters[i] = append(q.waiters[i][:j], q.waiters[i][j+1:]...)
+				removed = true
+				break
+			}
+		}
+
+		q.mu.Unlock()
+		if !removed {
+			// the turn has already been passed to us
+			q.release()
+		}
+
+		return ctx.Err()
+	}
+}
+
+func (q *priorityQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i := len(q.waiters) - 1; i >= 0; i-- {
+		if len(q.waiters[i]) > 0 {
+			ready := q.waiters[i][0]
+			q.waiters[i] = q.waiters[i][1:]
+			close(ready)
+			return
+		}
+	}
+
+	q.busy = false
+}
+
+// enter and exit track the number of requests waiting in flood control.
+func (q *priorityQueue) enter(priority Priority) {
+	atomic.AddInt64(&q.depth[priority.index()], 1)
+}
+
+func (q *priorityQueue) exit(priority Priority) {
+	atomic.AddInt64(&q.depth[priority.index()], -1)
+}
+
+// QueueDepth returns the number of requests waiting in flood control per priority.
+func (c *floodControlAware) QueueDepth() map[Priority]int {
+	depth := make(map[Priority]int, len(Priorities))
+	for _, priority := range Priorities {
+		depth[priority] = int(atomic.LoadInt64(&c.queue.depth[priority.index()]))
+	}
+
+	return depth
+}
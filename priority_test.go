@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/stretchr/testify/assert"
+)
+
+type executorFunc func(ctx context.Context, method string, body flu.EncoderTo, resp interface{}) error
+
+func (fun executorFunc) Execute(ctx context.Context, method string, body flu.EncoderTo, resp interface{}) error {
+	return fun(ctx, method, body, resp)
+}
+
+// waitQueued waits until the queue has n waiters with the priority.
+func waitQueued(t *testing.T, q *priorityQueue, priority Priority, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		q.mu.Lock()
+		queued := len(q.waiters[priority.index()])
+		q.mu.Unlock()
+		if queued == n {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d waiters with priority %d, got %d", n, priority, queued)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPriorityQueue_Overtake(t *testing.T) {
+	ctx := context.Background()
+	q := new(priorityQueue)
+	assert.NoError(t, q.acquire(ctx, NormalPriority))
+
+	order := make(chan Priority, 3)
+	enqueue := func(priority Priority, n int) {
+		go func() {
+			if err := q.acquire(ctx, priority); err == nil {
+				order <- priority
+				q.release()
+			}
+		}()
+
+		waitQueued(t, q, priority, n)
+	}
+
+	enqueue(LowPriority, 1)
+	enqueue(LowPriority, 2)
+	enqueue(HighPriority, 1)
+
+	q.release()
+	assert.Equal(t, HighPriority, <-order)
+	assert.Equal(t, LowPriority, <-order)
+	assert.Equal(t, LowPriority, <-order)
+}
+
+func TestPriorityQueue_Cancel(t *testing.T) {
+	q := new(priorityQueue)
+	assert.NoError(t, q.acquire(context.Background(), NormalPriority))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() { result <- q.acquire(ctx, LowPriority) }()
+	waitQueued(t, q, LowPriority, 1)
+
+	cancel()
+	assert.ErrorIs(t, <-result, context.Canceled)
+	waitQueued(t, q, LowPriority, 0)
+
+	q.release()
+	assert.False(t, q.busy)
+}
+
+func TestPriorityQueue_CancelAfterTurn(t *testing.T) {
+	q := new(priorityQueue)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, q.acquire(context.Background(), NormalPriority))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := make(chan error)
+		go func() { result <- q.acquire(ctx, NormalPriority) }()
+		waitQueued(t, q, NormalPriority, 1)
+
+		// The turn may be passed to the waiter while it is being cancelled.
+		cancel()
+		q.release()
+		if err := <-result; err == nil {
+			q.release()
+		}
+
+		assert.False(t, q.busy, "turn leaked")
+	}
+}
+
+func TestFloodControlAware_QueueDepth(t *testing.T) {
+	c := new(floodControlAware)
+	assert.Equal(t, map[Priority]int{HighPriority: 0, NormalPriority: 0, LowPriority: 0}, c.QueueDepth())
+
+	c.queue.enter(HighPriority)
+	c.queue.enter(LowPriority)
+	c.queue.enter(LowPriority)
+	assert.Equal(t, map[Priority]int{HighPriority: 1, NormalPriority: 0, LowPriority: 2}, c.QueueDepth())
+
+	c.queue.exit(LowPriority)
+	assert.Equal(t, map[Priority]int{HighPriority: 1, NormalPriority: 0, LowPriority: 1}, c.QueueDepth())
+}
+
+func TestFloodControlAware_PriorityBeforeChatLock(t *testing.T) {
+	ctx := context.Background()
+	executed := make(chan Priority, 2)
+	c := &floodControlAware{
+		executor: executorFunc(func(ctx context.Context, method string, body flu.EncoderTo, resp interface{}) error {
+			executed <- contextPriority(ctx)
+			return nil
+		}),
+		limits: RateLimits{Global: RateLimit{Rate: 1000, Interval: time.Second, Burst: 1000}},
+	}
+
+	c.once.Do(c.init)
+	c.createLimiter(&Chat{ID: 1, Type: PrivateChat})
+	assert.NoError(t, c.queue.acquire(ctx, NormalPriority))
+
+	execute := func(priority Priority) {
+		go func() { _ = c.execute(WithPriority(ctx, priority), ID(1), "sendMessage", nil, nil) }()
+		waitQueued(t, &c.queue, priority, 1)
+	}
+
+	// The high priority request must not wait for the chat lock behind the queued low priority one.
+	execute(LowPriority)
+	execute(HighPriority)
+
+	c.queue.release()
+	assert.Equal(t, HighPriority, <-executed)
+	assert.Equal(t, LowPriority, <-executed)
+}
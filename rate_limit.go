@@ -0,0 +1,174 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/jfk9w-go/flu/syncf"
+)
+
+// RateLimit is a token bucket limit: Rate requests are allowed per Interval
+// with at most Burst requests in a row.
+// Zero RateLimit means no limit.
+type RateLimit struct {
+	Rate     int
+	Interval time.Duration
+	// Burst is the bucket capacity. Defaults to 1.
+	Burst int
+}
+
+// RateLimits configures Bot flood control.
+// See https://core.telegram.org/bots/faq#my-bot-is-hitting-limits-how-do-i-avoid-this
+type RateLimits struct {
+	// Global limits all requests made by the Bot through flood control.
+	Global RateLimit
+	// Chat limits requests to every single chat depending on its type.
+	Chat map[ChatType]RateLimit
+	// ChatType limits requests to all chats of a given type combined.
+	ChatType map[ChatType]RateLimit
+}
+
+// DefaultRateLimits returns rate limits used by default.
+// These follow the limits stated in Bot API FAQ: about 30 messages per second overall,
+// about one message per second in a private chat and 20 messages per minute in a group or a channel.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Global: RateLimit{Rate: 30, Interval: time.Second, Burst: 30},
+		Chat: map[ChatType]RateLimit{
+			PrivateChat: {Rate: 1, Interval: time.Second, Burst: 3},
+			GroupChat:   {Rate: 20, Interval: time.Minute, Burst: 3},
+			Supergroup:  {Rate: 20, Interval: time.Minute, Burst: 3},
+			Channel:     {Rate: 20, Interval: time.Minute, Burst: 3},
+		},
+	}
+}
+
+// tokenBucket is a token bucket rate limiter.
+// Its methods are safe to call on nil receiver (which means no limit).
+type tokenBucket struct {
+	clock       syncf.Clock
+	every       time.Duration
+	burst       float64
+	tokens      float64
+	updated     time.Time
+	pausedUntil time.Time
+	mu          sync.Mutex
+}
+
+func newTokenBucket(clock syncf.Clock, limit RateLimit) *tokenBucket {
+	if limit.Rate <= 0 || limit.Interval <= 0 {
+		return nil
+	}
+
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		clock:  clock,
+		every:  limit.Interval / time.Duration(limit.Rate),
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.updated.IsZero() {
+		if elapsed := now.Sub(b.updated); elapsed > 0 {
+			b.tokens += float64(elapsed) / float64(b.every)
+			if b.tokens > b.burst {
+				b.tokens = b.burst
+			}
+		}
+	}
+
+	b.updated = now
+}
+
+// reserve takes a token and returns the duration to wait before it can be used.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	b.refill(now)
+	b.tokens--
+
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens * float64(b.every))
+	}
+
+	if pause := b.pausedUntil.Sub(now); pause > wait {
+		wait = pause
+	}
+
+	return wait
+}
+
+// cancel returns a reserved token.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// wait blocks until a token is available.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	if wait := b.reserve(); wait > 0 {
+		if err := flu.Sleep(ctx, wait); err != nil {
+			b.cancel()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pause drains the bucket and blocks it for the duration.
+// It is used to adapt to retry_after responses.
+func (b *tokenBucket) pause(timeout time.Duration) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	b.refill(now)
+	if b.tokens > 0 {
+		b.tokens = 0
+	}
+
+	if until := now.Add(timeout); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// chatLimiter limits requests to a single chat.
+type chatLimiter struct {
+	// lock serializes requests to the chat in order to preserve message order.
+	lock syncf.Locker
+	chat *tokenBucket
+	// chatType is shared between all chats of the same type.
+	chatType *tokenBucket
+	// kind is the chat type used for metrics.
+	kind ChatType
+}
+
+func (l *chatLimiter) wait(ctx context.Context) error {
+	if err := l.chat.wait(ctx); err != nil {
+		return err
+	}
+
+	return l.chatType.wait(ctx)
+}
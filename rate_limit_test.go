@@ -0,0 +1,73 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_Burst(t *testing.T) {
+	clock := newTestClock()
+	bucket := newTokenBucket(clock, RateLimit{Rate: 1, Interval: time.Second, Burst: 3})
+	for i := 0; i < 3; i++ {
+		assert.Zero(t, bucket.reserve())
+	}
+
+	assert.Equal(t, time.Second, bucket.reserve())
+	assert.Equal(t, 2*time.Second, bucket.reserve())
+}
+
+func TestTokenBucket_Refill(t *testing.T) {
+	clock := newTestClock()
+	bucket := newTokenBucket(clock, RateLimit{Rate: 2, Interval: time.Second, Burst: 2})
+	assert.Zero(t, bucket.reserve())
+	assert.Zero(t, bucket.reserve())
+	assert.Equal(t, 500*time.Millisecond, bucket.reserve())
+
+	// The reserved token is paid back first.
+	clock.Add(500 * time.Millisecond)
+	assert.Equal(t, 500*time.Millisecond, bucket.reserve())
+
+	// Refill never exceeds the burst.
+	clock.Add(time.Hour)
+	assert.Zero(t, bucket.reserve())
+	assert.Zero(t, bucket.reserve())
+	assert.Equal(t, 500*time.Millisecond, bucket.reserve())
+}
+
+func TestTokenBucket_Cancel(t *testing.T) {
+	clock := newTestClock()
+	bucket := newTokenBucket(clock, RateLimit{Rate: 1, Interval: time.Second})
+	assert.NoError(t, bucket.wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, bucket.wait(ctx), context.Canceled)
+
+	// The cancelled waiter must not keep its token.
+	assert.Equal(t, time.Second, bucket.reserve())
+}
+
+func TestTokenBucket_Pause(t *testing.T) {
+	clock := newTestClock()
+	bucket := newTokenBucket(clock, RateLimit{Rate: 1, Interval: time.Second, Burst: 3})
+	bucket.pause(5 * time.Second)
+	assert.Equal(t, 5*time.Second, bucket.reserve())
+
+	// A shorter pause does not shorten the current one.
+	bucket.pause(time.Second)
+	clock.Add(2 * time.Second)
+	assert.Equal(t, 3*time.Second, bucket.reserve())
+
+	clock.Add(3 * time.Second)
+	assert.Zero(t, bucket.reserve())
+}
+
+func TestTokenBucket_NoLimit(t *testing.T) {
+	bucket := newTokenBucket(newTestClock(), RateLimit{})
+	assert.Nil(t, bucket)
+	assert.NoError(t, bucket.wait(context.Background()))
+	bucket.pause(time.Second)
+}
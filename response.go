@@ -37,8 +37,14 @@ func (r *response) DecodeFrom(reader io.Reader) error {
 	}
 
 	if !r.Ok {
-		if r.Parameters != nil && r.Parameters.RetryAfter > 0 {
-			return TooManyMessages{time.Duration(r.Parameters.RetryAfter) * time.Second}
+		if r.Parameters != nil {
+			if r.Parameters.RetryAfter > 0 {
+				return TooManyMessages{time.Duration(r.Parameters.RetryAfter) * time.Second}
+			}
+
+			if r.Parameters.MigrateToChatID != 0 {
+				return ChatMigrated{r.Parameters.MigrateToChatID}
+			}
 		}
 
 		return Error{r.ErrorCode, r.Description}
@@ -68,3 +74,13 @@ type TooManyMessages struct {
 func (e TooManyMessages) Error() string {
 	return fmt.Sprintf("too many messages, retry after %.0f seconds", e.RetryAfter.Seconds())
 }
+
+// ChatMigrated is returned when the group has been migrated to a supergroup.
+// The request should be repeated with the new chat ID.
+type ChatMigrated struct {
+	MigrateToChatID ID
+}
+
+func (e ChatMigrated) Error() string {
+	return fmt.Sprintf("group has been migrated to a supergroup with id %d", e.MigrateToChatID)
+}
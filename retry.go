@@ -0,0 +1,113 @@
+package telegram
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/jfk9w-go/flu/backoff"
+	"github.com/jfk9w-go/flu/syncf"
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy describes how failed API calls are retried.
+// RetryPolicy{MaxAttempts: 1} disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one.
+	MaxAttempts int
+	// Backoff is used to calculate timeouts before retries when Bot API does not specify retry_after.
+	// No timeout is used if Backoff is nil.
+	Backoff backoff.Interface
+	// Jitter is the maximum fraction of the backoff timeout which is randomly added to or subtracted from it.
+	Jitter float64
+	// MaxRetryAfter is the maximum retry_after which will be waited for.
+	// TooManyMessages errors with longer retry_after are returned immediately.
+	// Zero means no limit.
+	MaxRetryAfter time.Duration
+	// Retryable checks if the error is retryable.
+	// IsRetryable is used by default.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by default.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		Backoff:     backoff.Exp{Base: 100 * time.Millisecond, Factor: 2},
+		Jitter:      0.2,
+	}
+}
+
+// IsRetryable checks if the error is transient:
+// flood control errors, Bot API server errors and errors which are not Bot API errors (e.g. network errors).
+// Wrapped errors are unwrapped with errors.As.
+func IsRetryable(err error) bool {
+	var (
+		tooManyMessages TooManyMessages
+		chatMigrated    ChatMigrated
+		apiErr          Error
+	)
+
+	switch {
+	case err == nil, errors.As(err, &chatMigrated):
+		return false
+	case errors.As(err, &tooManyMessages):
+		return true
+	case errors.As(err, &apiErr):
+		return apiErr.ErrorCode >= http.StatusInternalServerError
+	default:
+		return !syncf.IsContextRelated(err)
+	}
+}
+
+// next returns the timeout before the next attempt and whether the next attempt should be made.
+func (p RetryPolicy) next(attempt int, err error) (time.Duration, bool) {
+	if err == nil || attempt >= p.MaxAttempts || syncf.IsContextRelated(err) {
+		return 0, false
+	}
+
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	if !retryable(err) {
+		return 0, false
+	}
+
+	var tooManyMessages TooManyMessages
+	if errors.As(err, &tooManyMessages) {
+		if p.MaxRetryAfter > 0 && tooManyMessages.RetryAfter > p.MaxRetryAfter {
+			return 0, false
+		}
+
+		return tooManyMessages.RetryAfter, true
+	}
+
+	if p.Backoff == nil {
+		return 0, true
+	}
+
+	timeout := p.Backoff.Timeout(attempt)
+	if p.Jitter > 0 {
+		timeout += time.Duration((2*rand.Float64() - 1) * p.Jitter * float64(timeout))
+	}
+
+	return timeout, true
+}
+
+type retryPolicyKey struct{}
+
+// WithRetryPolicy overrides the retry policy for API calls made with the context.
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+func contextRetryPolicy(ctx context.Context, defaultPolicy RetryPolicy) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		return policy
+	}
+
+	return defaultPolicy
+}
@@ -0,0 +1,112 @@
+package telegram
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jfk9w-go/flu/backoff"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_MaxAttempts(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+	err := Error{ErrorCode: 500, Description: "Internal Server Error"}
+	for attempt := 1; attempt < 3; attempt++ {
+		timeout, ok := policy.next(attempt, err)
+		assert.True(t, ok)
+		assert.Zero(t, timeout)
+	}
+
+	_, ok := policy.next(3, err)
+	assert.False(t, ok)
+
+	_, ok = RetryPolicy{MaxAttempts: 1}.next(1, err)
+	assert.False(t, ok)
+}
+
+func TestRetryPolicy_NotRetryable(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+	for _, err := range []error{
+		nil,
+		Error{ErrorCode: 400, Description: "Bad Request"},
+		ChatMigrated{MigrateToChatID: -100},
+		context.Canceled,
+		context.DeadlineExceeded,
+	} {
+		_, ok := policy.next(1, err)
+		assert.False(t, ok, "%v", err)
+	}
+
+	_, ok := policy.next(1, io.ErrUnexpectedEOF)
+	assert.True(t, ok)
+
+	policy.Retryable = func(error) bool { return false }
+	_, ok = policy.next(1, io.ErrUnexpectedEOF)
+	assert.False(t, ok)
+}
+
+func TestRetryPolicy_MaxRetryAfter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, MaxRetryAfter: 10 * time.Second}
+
+	timeout, ok := policy.next(1, TooManyMessages{RetryAfter: 5 * time.Second})
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, timeout)
+
+	timeout, ok = policy.next(1, TooManyMessages{RetryAfter: 10 * time.Second})
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, timeout)
+
+	_, ok = policy.next(1, TooManyMessages{RetryAfter: 11 * time.Second})
+	assert.False(t, ok)
+}
+
+func TestRetryPolicy_Jitter(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 10,
+		Backoff:     backoff.Exp{Base: 100 * time.Millisecond, Factor: 2},
+		Jitter:      0.2,
+	}
+
+	err := io.ErrUnexpectedEOF
+	for attempt := 1; attempt < 5; attempt++ {
+		base := policy.Backoff.Timeout(attempt)
+		min, max := base-base/5, base+base/5
+		for i := 0; i < 100; i++ {
+			timeout, ok := policy.next(attempt, err)
+			assert.True(t, ok)
+			assert.GreaterOrEqual(t, timeout, min)
+			assert.LessOrEqual(t, timeout, max)
+		}
+	}
+
+	policy.Jitter = 0
+	timeout, ok := policy.next(2, err)
+	assert.True(t, ok)
+	assert.Equal(t, policy.Backoff.Timeout(2), timeout)
+}
+
+func TestRetryPolicy_WrappedErrors(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, MaxRetryAfter: 10 * time.Second}
+
+	_, ok := policy.next(1, errors.Wrap(Error{ErrorCode: 403, Description: "Forbidden"}, "send"))
+	assert.False(t, ok)
+
+	_, ok = policy.next(1, errors.Wrap(ChatMigrated{MigrateToChatID: -100}, "send"))
+	assert.False(t, ok)
+
+	_, ok = policy.next(1, errors.Wrap(Error{ErrorCode: 502, Description: "Bad Gateway"}, "send"))
+	assert.True(t, ok)
+
+	timeout, ok := policy.next(1, errors.Wrap(TooManyMessages{RetryAfter: 3 * time.Second}, "send"))
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, timeout)
+
+	_, ok = policy.next(1, errors.Wrap(TooManyMessages{RetryAfter: time.Minute}, "send"))
+	assert.False(t, ok)
+
+	_, ok = policy.next(1, errors.Wrap(context.Canceled, "send"))
+	assert.False(t, ok)
+}
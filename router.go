@@ -0,0 +1,309 @@
+package telegram
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// UpdateHandler handles updates.
+type UpdateHandler interface {
+	OnUpdate(ctx context.Context, client Client, update *Update) error
+}
+
+type UpdateHandlerFunc func(ctx context.Context, client Client, update *Update) error
+
+func (fun UpdateHandlerFunc) OnUpdate(ctx context.Context, client Client, update *Update) error {
+	return fun(ctx, client, update)
+}
+
+// Filter checks if the update should be handled.
+type Filter func(client Client, update *Update) bool
+
+// And returns a Filter matching updates which match both filters.
+func (f Filter) And(other Filter) Filter {
+	return All(f, other)
+}
+
+// Or returns a Filter matching updates which match any of the filters.
+func (f Filter) Or(other Filter) Filter {
+	return Any(f, other)
+}
+
+// Not returns a Filter matching updates which do not match the filter.
+func (f Filter) Not() Filter {
+	return func(client Client, update *Update) bool {
+		return !f(client, update)
+	}
+}
+
+// All returns a Filter matching updates which match all filters.
+// It matches all updates if no filters are provided.
+func All(filters ...Filter) Filter {
+	return func(client Client, update *Update) bool {
+		for _, filter := range filters {
+			if !filter(client, update) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any returns a Filter matching updates which match any of the filters.
+func Any(filters ...Filter) Filter {
+	return func(client Client, update *Update) bool {
+		for _, filter := range filters {
+			if filter(client, update) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// OfType matches updates of the provided types (UpdateMessage, UpdateCallbackQuery, etc.).
+func OfType(updateTypes ...string) Filter {
+	return func(client Client, update *Update) bool {
+		updateType := update.kind()
+		for _, allowed := range updateTypes {
+			if updateType == allowed {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// InChatType matches updates from chats of the provided types.
+func InChatType(chatTypes ...ChatType) Filter {
+	return func(client Client, update *Update) bool {
+		chat := updateChat(update)
+		if chat == nil {
+			return false
+		}
+
+		for _, chatType := range chatTypes {
+			if chat.Type == chatType {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// InChat matches updates from the chats.
+func InChat(chatIDs ...ID) Filter {
+	return func(client Client, update *Update) bool {
+		chat := updateChat(update)
+		return chat != nil && containsID(chatIDs, chat.ID)
+	}
+}
+
+// FromUser matches updates from the users.
+func FromUser(userIDs ...ID) Filter {
+	return func(client Client, update *Update) bool {
+		user := updateUser(update)
+		return user != nil && containsID(userIDs, user.ID)
+	}
+}
+
+// TextMatches matches messages with text or caption matching the regular expression.
+func TextMatches(re *regexp.Regexp) Filter {
+	return func(client Client, update *Update) bool {
+		message := updateMessage(update)
+		if message == nil {
+			return false
+		}
+
+		text, _ := message.entitySource()
+		return re.MatchString(text)
+	}
+}
+
+// HasMedia matches messages containing an animation, an audio, a document, a photo,
+// a sticker, a video, a video note or a voice.
+func HasMedia() Filter {
+	return func(client Client, update *Update) bool {
+		message := updateMessage(update)
+		if message == nil {
+			return false
+		}
+
+		switch message.Kind() {
+		case AnimationMessage, AudioMessage, DocumentMessage, PhotoMessage,
+			StickerMessage, VideoMessage, VideoNoteMessage, VoiceMessage:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// ReplyToBot matches messages which are replies to messages sent by the bot.
+func ReplyToBot() Filter {
+	return func(client Client, update *Update) bool {
+		message := updateMessage(update)
+		if message == nil || message.ReplyToMessage == nil {
+			return false
+		}
+
+		from := message.ReplyToMessage.From
+		return from.IsBot && from.Username != nil && *from.Username == client.Username()
+	}
+}
+
+type route struct {
+	filter   Filter
+	handler  UpdateHandler
+	priority int
+}
+
+// Router is an UpdateHandler which dispatches updates to the first handler with matching filter.
+// Handlers with higher priority are checked first, handlers with equal priority are checked
+// in the order they were added. Updates which do not match any filter are passed to the fallback handler.
+// Router may be used with Bot.UpdateListener or by calling OnUpdate directly (for example, on updates from Listen).
+type Router struct {
+	routes   []route
+	fallback UpdateHandler
+	mu       sync.RWMutex
+}
+
+// Handle adds a handler with the default (zero) priority.
+func (r *Router) Handle(filter Filter, handler UpdateHandler) *Router {
+	return r.HandlePriority(0, filter, handler)
+}
+
+func (r *Router) HandleFunc(filter Filter, fun UpdateHandlerFunc) *Router {
+	return r.Handle(filter, fun)
+}
+
+// HandlePriority adds a handler with the provided priority.
+// A nil filter matches all updates.
+func (r *Router) HandlePriority(priority int, filter Filter, handler UpdateHandler) *Router {
+	if filter == nil {
+		filter = All()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, route{filter, handler, priority})
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		return r.routes[i].priority > r.routes[j].priority
+	})
+
+	return r
+}
+
+// Fallback sets the handler for updates which do not match any filter.
+func (r *Router) Fallback(handler UpdateHandler) *Router {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = handler
+	return r
+}
+
+func (r *Router) FallbackFunc(fun UpdateHandlerFunc) *Router {
+	return r.Fallback(fun)
+}
+
+func (r *Router) OnUpdate(ctx context.Context, client Client, update *Update) error {
+	if handler := r.match(client, update); handler != nil {
+		return handler.OnUpdate(ctx, client, update)
+	}
+
+	return nil
+}
+
+func (r *Router) match(client Client, update *Update) UpdateHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, route := range r.routes {
+		if route.filter(client, update) {
+			return route.handler
+		}
+	}
+
+	return r.fallback
+}
+
+// updateMessage returns the message the update is about, if any.
+func updateMessage(update *Update) *Message {
+	switch {
+	case update.Message != nil:
+		return update.Message
+	case update.EditedMessage != nil:
+		return update.EditedMessage
+	case update.ChannelPost != nil:
+		return update.ChannelPost
+	case update.EditedChannelPost != nil:
+		return update.EditedChannelPost
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.Message
+	default:
+		return nil
+	}
+}
+
+// updateChat returns the chat where the update comes from, if any.
+func updateChat(update *Update) *Chat {
+	switch {
+	case update.MyChatMember != nil:
+		return &update.MyChatMember.Chat
+	case update.ChatMember != nil:
+		return &update.ChatMember.Chat
+	case update.ChatJoinRequest != nil:
+		return &update.ChatJoinRequest.Chat
+	}
+
+	if message := updateMessage(update); message != nil {
+		return &message.Chat
+	}
+
+	return nil
+}
+
+// updateUser returns the user who caused the update, if any.
+func updateUser(update *Update) *User {
+	switch {
+	case update.InlineQuery != nil:
+		return &update.InlineQuery.From
+	case update.ChosenInlineResult != nil:
+		return &update.ChosenInlineResult.From
+	case update.CallbackQuery != nil:
+		return &update.CallbackQuery.From
+	case update.ShippingQuery != nil:
+		return &update.ShippingQuery.From
+	case update.PreCheckoutQuery != nil:
+		return &update.PreCheckoutQuery.From
+	case update.PollAnswer != nil:
+		return update.PollAnswer.User
+	case update.MyChatMember != nil:
+		return &update.MyChatMember.From
+	case update.ChatMember != nil:
+		return &update.ChatMember.From
+	case update.ChatJoinRequest != nil:
+		return &update.ChatJoinRequest.From
+	}
+
+	if message := updateMessage(update); message != nil && message.From.ID != 0 {
+		return &message.From
+	}
+
+	return nil
+}
+
+func containsID(ids []ID, id ID) bool {
+	for _, value := range ids {
+		if value == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,176 @@
+package telegram_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilters(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	me, err := f.Bot.GetMe(timeout(t))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var (
+		user    = telegram.User{ID: 2, FirstName: "User"}
+		private = telegram.Chat{ID: 2, Type: telegram.PrivateChat}
+		group   = telegram.Chat{ID: -10, Type: telegram.Supergroup}
+
+		text = &telegram.Update{Message: &telegram.Message{
+			Chat: private, From: user, Text: "hello world",
+		}}
+		caption = &telegram.Update{EditedMessage: &telegram.Message{
+			Chat: group, From: user, Caption: "hello photo", Photo: []telegram.PhotoSize{{ID: "photo"}},
+		}}
+		reply = &telegram.Update{Message: &telegram.Message{
+			Chat: group, From: user, Text: "reply",
+			ReplyToMessage: &telegram.Message{Chat: group, From: *me, Text: "question"},
+		}}
+		replyToUser = &telegram.Update{Message: &telegram.Message{
+			Chat: group, From: user, Text: "reply",
+			ReplyToMessage: &telegram.Message{Chat: group, From: user, Text: "question"},
+		}}
+		callback = &telegram.Update{CallbackQuery: &telegram.CallbackQuery{
+			From: user, Message: &telegram.Message{Chat: group, From: *me, Text: "buttons"},
+		}}
+		inline = &telegram.Update{InlineQuery: &telegram.InlineQuery{From: user, Query: "hello"}}
+		member = &telegram.Update{MyChatMember: &telegram.ChatMemberUpdated{Chat: group, From: user}}
+	)
+
+	hello := telegram.TextMatches(regexp.MustCompile(`^hello`))
+	for _, tc := range []struct {
+		name    string
+		filter  telegram.Filter
+		update  *telegram.Update
+		matches bool
+	}{
+		{"of type", telegram.OfType(telegram.UpdateMessage), text, true},
+		{"of type multiple", telegram.OfType(telegram.UpdateMessage, telegram.UpdateCallbackQuery), callback, true},
+		{"of type other", telegram.OfType(telegram.UpdateMessage), caption, false},
+		{"in chat type", telegram.InChatType(telegram.GroupChat, telegram.Supergroup), caption, true},
+		{"in chat type member", telegram.InChatType(telegram.Supergroup), member, true},
+		{"in chat type other", telegram.InChatType(telegram.GroupChat), text, false},
+		{"in chat type no chat", telegram.InChatType(telegram.PrivateChat), inline, false},
+		{"in chat", telegram.InChat(1, -10), callback, true},
+		{"in chat other", telegram.InChat(1, -10), text, false},
+		{"from user", telegram.FromUser(2), inline, true},
+		{"from user other", telegram.FromUser(3), text, false},
+		{"text matches", hello, text, true},
+		{"text matches caption", hello, caption, true},
+		{"text matches other", hello, reply, false},
+		{"text matches no message", hello, inline, false},
+		{"has media", telegram.HasMedia(), caption, true},
+		{"has media text", telegram.HasMedia(), text, false},
+		{"reply to bot", telegram.ReplyToBot(), reply, true},
+		{"reply to user", telegram.ReplyToBot(), replyToUser, false},
+		{"reply to bot not a reply", telegram.ReplyToBot(), text, false},
+		{"not", hello.Not(), text, false},
+		{"not other", hello.Not(), reply, true},
+		{"and", hello.And(telegram.HasMedia()), caption, true},
+		{"and other", hello.And(telegram.HasMedia()), text, false},
+		{"or", telegram.HasMedia().Or(telegram.ReplyToBot()), reply, true},
+		{"or other", telegram.HasMedia().Or(telegram.ReplyToBot()), text, false},
+		{"all empty", telegram.All(), inline, true},
+		{"all", telegram.All(hello, telegram.FromUser(2), telegram.InChat(2)), text, true},
+		{"all other", telegram.All(hello, telegram.FromUser(2), telegram.InChat(3)), text, false},
+		{"any empty", telegram.Any(), inline, false},
+		{"any", telegram.Any(telegram.HasMedia(), telegram.InChat(2)), text, true},
+		{"any other", telegram.Any(telegram.HasMedia(), telegram.InChat(3)), text, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.matches, tc.filter(f.Bot, tc.update))
+		})
+	}
+}
+
+func TestRouter_OnUpdate(t *testing.T) {
+	var routed string
+	route := func(name string) telegram.UpdateHandlerFunc {
+		return func(ctx context.Context, client telegram.Client, update *telegram.Update) error {
+			routed = name
+			return nil
+		}
+	}
+
+	router := new(telegram.Router).
+		HandleFunc(telegram.InChat(1), route("first")).
+		HandleFunc(telegram.InChat(1, 2), route("second")).
+		HandlePriority(1, telegram.FromUser(3), route("priority")).
+		HandlePriority(-1, nil, route("any"))
+
+	update := func(chatID, userID telegram.ID) *telegram.Update {
+		return &telegram.Update{Message: &telegram.Message{
+			Chat: telegram.Chat{ID: chatID, Type: telegram.PrivateChat},
+			From: telegram.User{ID: userID},
+		}}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		update *telegram.Update
+		routed string
+	}{
+		{"added first", update(1, 1), "first"},
+		{"added second", update(2, 1), "second"},
+		{"higher priority", update(1, 3), "priority"},
+		{"nil filter", update(4, 1), "any"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			routed = ""
+			assert.NoError(t, router.OnUpdate(context.Background(), nil, tc.update))
+			assert.Equal(t, tc.routed, routed)
+		})
+	}
+
+	router = new(telegram.Router).HandleFunc(telegram.InChat(1), route("chat"))
+	routed = ""
+	assert.NoError(t, router.OnUpdate(context.Background(), nil, update(2, 1)))
+	assert.Empty(t, routed)
+
+	router.FallbackFunc(route("fallback"))
+	assert.NoError(t, router.OnUpdate(context.Background(), nil, update(2, 1)))
+	assert.Equal(t, "fallback", routed)
+}
+
+func TestBot_UpdateListener(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	routes := make(chan string, 1)
+	route := func(name string) telegram.UpdateHandlerFunc {
+		return func(ctx context.Context, client telegram.Client, update *telegram.Update) error {
+			routes <- name
+			return nil
+		}
+	}
+
+	f.Bot.UpdateListener(new(telegram.Router).
+		HandleFunc(telegram.TextMatches(regexp.MustCompile(`^hello`)), route("hello")).
+		FallbackFunc(route("fallback")))
+
+	f.Server.SendMessage(f.User.ID, f.Chat.ID, "hello world")
+	assert.Equal(t, "hello", receive(t, routes, "route"))
+
+	f.Server.SendMessage(f.User.ID, f.Chat.ID, "bye")
+	assert.Equal(t, "fallback", receive(t, routes, "route"))
+}
+
+func TestBot_UpdateListenerHandlerError(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	handled := make(chan string, 1)
+	f.Bot.UpdateListenerFunc(func(ctx context.Context, client telegram.Client, update *telegram.Update) error {
+		handled <- update.Message.Text
+		// For example, a request made by the handler timed out.
+		return context.DeadlineExceeded
+	}, telegram.UpdateMessage)
+
+	for _, text := range []string{"first", "second"} {
+		f.Server.SendMessage(f.User.ID, f.Chat.ID, text)
+		assert.Equal(t, text, receive(t, handled, "update"))
+	}
+}
@@ -2,6 +2,7 @@ package telegram
 
 import (
 	"encoding/json"
+	"path/filepath"
 	"strconv"
 
 	"github.com/jfk9w-go/flu/httpf"
@@ -57,6 +58,8 @@ func (mt MediaType) RemoteMaxSize() int64 {
 	}
 }
 
+// AttachMaxSize returns the maximum upload size for the media type accepted by the cloud Bot API server.
+// See Bot.AttachMaxSize for limits which take WithLocalMode into account.
 func (mt MediaType) AttachMaxSize() int64 {
 	if mt == Photo {
 		return 10 << 20
@@ -132,6 +135,16 @@ func (m Media) self() Sendable {
 	return m
 }
 
+func (m Media) localize() Media {
+	if file, ok := m.Input.(flu.File); ok {
+		if path, err := filepath.Abs(file.String()); err == nil {
+			m.Input = flu.URL("file://" + filepath.ToSlash(path))
+		}
+	}
+
+	return m
+}
+
 type mediaJSON struct {
 	Media
 	MediaURL string `json:"media"`
@@ -145,25 +158,9 @@ func (mg MediaGroup) kind() string {
 
 func (mg MediaGroup) body(form *httpf.Form) (flu.EncoderTo, error) {
 	var multipart *httpf.MultipartForm
-	multiparted := false
 	media := make([]mediaJSON, len(mg))
 	for i, m := range mg {
-		m := mediaJSON{m, ""}
-		switch r := m.Input.(type) {
-		case flu.URL:
-			m.MediaURL = r.String()
-		default:
-			if !multiparted {
-				multipart = form.Multipart()
-				multiparted = true
-			}
-
-			id := "media" + strconv.Itoa(i)
-			multipart = multipart.File(id, m.filename(), m.Input)
-			m.MediaURL = "attach://" + id
-		}
-
-		media[i] = m
+		media[i] = m.inputMedia(&multipart, form, "media"+strconv.Itoa(i))
 	}
 
 	bytes, err := json.Marshal(media)
@@ -171,8 +168,47 @@ func (mg MediaGroup) body(form *httpf.Form) (flu.EncoderTo, error) {
 		return nil, err
 	}
 	form = form.Set("media", string(bytes))
-	if multiparted {
+	if multipart != nil {
 		return multipart, nil
 	}
 	return form, nil
 }
+
+// inputMedia converts Media to InputMedia representation.
+// If the media needs to be uploaded, it is attached to multipart form (which is created from form if necessary).
+func (m Media) inputMedia(multipart **httpf.MultipartForm, form *httpf.Form, id string) mediaJSON {
+	media := mediaJSON{m, ""}
+	switch r := m.Input.(type) {
+	case flu.URL:
+		media.MediaURL = r.String()
+	default:
+		if *multipart == nil {
+			*multipart = form.Multipart()
+		}
+
+		*multipart = (*multipart).File(id, m.filename(), m.Input)
+		media.MediaURL = "attach://" + id
+	}
+
+	return media
+}
+
+// localize replaces flu.File inputs with file:// URIs supported by local Bot API servers.
+func localize(item sendable) sendable {
+	switch item := item.(type) {
+	case Media:
+		return item.localize()
+	case *Media:
+		media := item.localize()
+		return &media
+	case MediaGroup:
+		mg := make(MediaGroup, len(item))
+		for i, m := range item {
+			mg[i] = m.localize()
+		}
+
+		return mg
+	default:
+		return item
+	}
+}
@@ -0,0 +1,24 @@
+package telegram_test
+
+import (
+	"testing"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBot_AttachMaxSize(t *testing.T) {
+	local := telegram.NewBot(nil, nil, "123456:TEST-TOKEN", telegram.WithLocalMode())
+	defer local.Close()
+
+	remote := telegram.NewBot(nil, nil, "123456:TEST-TOKEN")
+	defer remote.Close()
+
+	assert.Equal(t, telegram.LocalAttachMaxSize, local.AttachMaxSize(telegram.Photo))
+	assert.Equal(t, telegram.LocalAttachMaxSize, local.AttachMaxSize(telegram.Video))
+
+	// Local mode of one bot must not affect others.
+	assert.Equal(t, int64(10<<20), remote.AttachMaxSize(telegram.Photo))
+	assert.Equal(t, int64(50<<20), remote.AttachMaxSize(telegram.Video))
+	assert.Equal(t, int64(10<<20), telegram.Photo.AttachMaxSize())
+}
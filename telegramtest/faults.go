@@ -0,0 +1,117 @@
+package telegramtest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+)
+
+// Error is a Bot API error response.
+type Error struct {
+	// Code is the error_code (which is also used as HTTP status code).
+	Code int
+	// Description is the human-readable error description.
+	Description string
+	// RetryAfter is the retry_after response parameter in seconds.
+	RetryAfter int
+	// MigrateToChatID is the migrate_to_chat_id response parameter.
+	MigrateToChatID telegram.ID
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s", e.Code, e.Description)
+}
+
+func badRequest(format string, args ...interface{}) *Error {
+	return &Error{Code: http.StatusBadRequest, Description: "Bad Request: " + fmt.Sprintf(format, args...)}
+}
+
+type fault struct {
+	method string
+	times  int
+	err    Error
+}
+
+// Fail makes the next `times` calls to the method fail with the error.
+// Empty method matches all methods except getUpdates.
+func (s *Server) Fail(method string, times int, err Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault{method: method, times: times, err: err})
+}
+
+// FloodWait makes the next `times` calls to the method fail with 429 Too Many Requests
+// and the specified retry_after (in seconds).
+// Empty method matches all methods except getUpdates.
+func (s *Server) FloodWait(method string, times int, retryAfter int) {
+	s.Fail(method, times, Error{
+		Code:        http.StatusTooManyRequests,
+		Description: fmt.Sprintf("Too Many Requests: retry after %d", retryAfter),
+		RetryAfter:  retryAfter,
+	})
+}
+
+// Block emulates the user blocking the bot: all messages sent to the user private chat
+// fail with 403 Forbidden.
+func (s *Server) Block(userID telegram.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocked[userID] = true
+}
+
+// Unblock reverts Block.
+func (s *Server) Unblock(userID telegram.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.blocked, userID)
+}
+
+// Migrate emulates the group upgrade to a supergroup.
+// A new supergroup chat is created, and all requests to the old group fail with migrate_to_chat_id set.
+func (s *Server) Migrate(groupID telegram.ID) *telegram.Chat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	supergroup := telegram.Chat{Type: telegram.Supergroup}
+	if group, ok := s.chats[groupID]; ok {
+		supergroup = *group
+		supergroup.Type = telegram.Supergroup
+	}
+
+	supergroup.ID = -1000000000000 + groupID
+	s.chats[supergroup.ID] = &supergroup
+	s.migrated[groupID] = supergroup.ID
+	return &supergroup
+}
+
+func (s *Server) checkFaults(call *Call) error {
+	for i, fault := range s.faults {
+		if fault.method == "" || fault.method == call.Method {
+			fault.times--
+			if fault.times <= 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+
+			err := fault.err
+			return &err
+		}
+	}
+
+	chatID, ok := s.lookupChatID(call.Param("chat_id"))
+	if !ok {
+		return nil
+	}
+
+	if newChatID, ok := s.migrated[chatID]; ok {
+		err := badRequest("group chat was upgraded to a supergroup chat")
+		err.MigrateToChatID = newChatID
+		return err
+	}
+
+	if s.blocked[chatID] && (strings.HasPrefix(call.Method, "send") || call.Method == "forwardMessage" || call.Method == "copyMessage") {
+		return &Error{Code: http.StatusForbidden, Description: "Forbidden: bot was blocked by the user"}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package telegramtest
+
+import (
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+)
+
+// Fixture is a Server with a single registered user and a telegram.Bot working with it.
+type Fixture struct {
+	Server *Server
+	Bot    *telegram.Bot
+	// User is the registered user.
+	User telegram.User
+	// Chat is the private chat with User.
+	Chat *telegram.Chat
+}
+
+// Setup starts a Server, registers a user and creates a telegram.Bot with the provided clock and options.
+// Both the server and the bot are closed when the test finishes.
+func Setup(t testing.TB, clock syncf.Clock, options ...telegram.BotOption) *Fixture {
+	server := NewServer()
+	user := telegram.User{ID: 1, FirstName: "User"}
+	chat := server.AddUser(user)
+	bot := server.NewBot(clock, options...)
+	t.Cleanup(func() {
+		_ = bot.Close()
+		server.Close()
+	})
+
+	return &Fixture{Server: server, Bot: bot, User: user, Chat: chat}
+}
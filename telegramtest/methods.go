@@ -0,0 +1,454 @@
+package telegramtest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+)
+
+const filePathPrefix = "files/"
+
+var methods = map[string]func(s *Server, call *Call) (interface{}, error){
+	"getMe":                  (*Server).getMe,
+	"sendChatAction":         (*Server).sendChatAction,
+	"sendMediaGroup":         (*Server).sendMediaGroup,
+	"forwardMessage":         (*Server).forwardMessage,
+	"copyMessage":            (*Server).copyMessage,
+	"editMessageText":        (*Server).editMessage,
+	"editMessageCaption":     (*Server).editMessage,
+	"editMessageReplyMarkup": (*Server).editMessage,
+	"editMessageMedia":       (*Server).editMessage,
+	"deleteMessage":          (*Server).deleteMessage,
+	"stopPoll":               (*Server).stopPoll,
+	"getChat":                (*Server).getChat,
+	"getChatMember":          (*Server).getChatMember,
+	"getChatAdministrators":  (*Server).getChatAdministrators,
+	"getChatMemberCount":     (*Server).getChatMemberCount,
+	"getFile":                (*Server).getFile,
+}
+
+// AddUser registers the user along with the private chat with the bot.
+func (s *Server) AddUser(user telegram.User) *telegram.Chat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.ID] = user
+	chat := &telegram.Chat{
+		ID:        user.ID,
+		Type:      telegram.PrivateChat,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	}
+
+	s.chats[chat.ID] = chat
+	return chat
+}
+
+// AddChat registers the chat.
+func (s *Server) AddChat(chat telegram.Chat) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats[chat.ID] = &chat
+}
+
+// AddFile registers the file which can be retrieved with getFile and downloaded.
+// Returns the file ID.
+func (s *Server) AddFile(data []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addFile(data)
+}
+
+// Messages returns all messages in the chat (both sent by the bot and by users).
+func (s *Server) Messages(chatID telegram.ID) []telegram.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := make([]telegram.Message, len(s.messages[chatID]))
+	for i, message := range s.messages[chatID] {
+		messages[i] = *message
+	}
+
+	return messages
+}
+
+func (s *Server) addFile(data []byte) string {
+	id := "file-" + strconv.Itoa(len(s.files)+1)
+	s.files[id] = data
+	return id
+}
+
+func (s *Server) lookupChatID(param string) (telegram.ID, bool) {
+	if strings.HasPrefix(param, "@") {
+		for id, chat := range s.chats {
+			if chat.Username != nil && string(*chat.Username) == param[1:] {
+				return id, true
+			}
+		}
+
+		return 0, false
+	}
+
+	id, err := telegram.ParseID(param)
+	return id, err == nil
+}
+
+func (s *Server) resolveChat(param string) (*telegram.Chat, error) {
+	if id, ok := s.lookupChatID(param); ok {
+		if chat, ok := s.chats[id]; ok {
+			return chat, nil
+		}
+	}
+
+	return nil, badRequest("chat not found")
+}
+
+func (s *Server) newMessage(chat *telegram.Chat, from telegram.User) *telegram.Message {
+	s.lastMessageID++
+	message := &telegram.Message{
+		ID:   s.lastMessageID,
+		From: from,
+		Date: int(time.Now().Unix()),
+		Chat: *chat,
+	}
+
+	s.messages[chat.ID] = append(s.messages[chat.ID], message)
+	return message
+}
+
+func (s *Server) findMessage(chatParam string, idParam string) (*telegram.Message, int, error) {
+	chat, err := s.resolveChat(chatParam)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	id, _ := telegram.ParseID(idParam)
+	for i, message := range s.messages[chat.ID] {
+		if message.ID == id {
+			return message, i, nil
+		}
+	}
+
+	return nil, 0, badRequest("message not found")
+}
+
+// fileID returns the file ID for the uploaded file or the passed file_id / URL.
+func (s *Server) fileID(call *Call, value string) string {
+	if strings.HasPrefix(value, "attach://") {
+		value = value[len("attach://"):]
+	} else if value != "" {
+		return value
+	}
+
+	if data, ok := call.Files[value]; ok {
+		return s.addFile(data)
+	}
+
+	return ""
+}
+
+func setMedia(message *telegram.Message, mediaType string, fileID string) {
+	message.Photo, message.Video, message.Animation, message.Document = nil, nil, nil, nil
+	message.Audio, message.Voice, message.Sticker, message.VideoNote = nil, nil, nil, nil
+	switch mediaType {
+	case "photo":
+		message.Photo = []telegram.PhotoSize{{ID: fileID, UniqueID: fileID}}
+	case "video":
+		message.Video = &telegram.VideoFile{ID: fileID, UniqueID: fileID}
+	case "animation":
+		message.Animation = &telegram.AnimationFile{ID: fileID, UniqueID: fileID}
+		message.Document = &telegram.DocumentFile{ID: fileID, UniqueID: fileID}
+	case "audio":
+		message.Audio = &telegram.AudioFile{ID: fileID, UniqueID: fileID}
+	case "voice":
+		message.Voice = &telegram.VoiceFile{ID: fileID, UniqueID: fileID}
+	case "sticker":
+		message.Sticker = &telegram.StickerFile{ID: fileID, UniqueID: fileID}
+	case "video_note":
+		message.VideoNote = &telegram.VideoNoteFile{ID: fileID, UniqueID: fileID}
+	default:
+		message.Document = &telegram.DocumentFile{ID: fileID, UniqueID: fileID}
+	}
+}
+
+func setReplyMarkup(message *telegram.Message, call *Call) {
+	message.ReplyMarkup = nil
+	if value := call.Param("reply_markup"); value != "" {
+		markup := new(telegram.InlineKeyboardMarkup)
+		if err := json.Unmarshal([]byte(value), markup); err == nil && len(markup.InlineKeyboard) > 0 {
+			message.ReplyMarkup = markup
+		}
+	}
+}
+
+func (s *Server) getMe(call *Call) (interface{}, error) {
+	return s.Me, nil
+}
+
+func (s *Server) sendChatAction(call *Call) (interface{}, error) {
+	if _, err := s.resolveChat(call.Param("chat_id")); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+func (s *Server) sendMessage(call *Call) (interface{}, error) {
+	chat, err := s.resolveChat(call.Param("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	message := s.newMessage(chat, s.Me)
+	mediaType := strings.ToLower(strings.TrimPrefix(call.Method, "send"))
+	switch mediaType {
+	case "message":
+		message.Text = call.Param("text")
+	case "poll":
+		poll := &telegram.Poll{
+			ID:          strconv.Itoa(int(message.ID)),
+			Question:    call.Param("question"),
+			IsAnonymous: call.Param("is_anonymous") != "false",
+			Type:        telegram.PollType(call.Param("type")),
+		}
+
+		var options []struct {
+			Text string `json:"text"`
+		}
+
+		_ = json.Unmarshal([]byte(call.Param("options")), &options)
+		for _, option := range options {
+			poll.Options = append(poll.Options, telegram.PollOption{Text: option.Text})
+		}
+
+		message.Poll = poll
+	case "videonote":
+		setMedia(message, "video_note", s.fileID(call, call.Param("video_note")))
+	default:
+		setMedia(message, mediaType, s.fileID(call, call.Param(mediaType)))
+	}
+
+	message.Caption = call.Param("caption")
+	setReplyMarkup(message, call)
+	if replyTo := call.Param("reply_to_message_id"); replyTo != "" {
+		if reply, _, err := s.findMessage(call.Param("chat_id"), replyTo); err == nil {
+			message.ReplyToMessage = reply
+		}
+	}
+
+	return message, nil
+}
+
+func (s *Server) sendMediaGroup(call *Call) (interface{}, error) {
+	chat, err := s.resolveChat(call.Param("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	var media []struct {
+		Type    string `json:"type"`
+		Media   string `json:"media"`
+		Caption string `json:"caption"`
+	}
+
+	if err := json.Unmarshal([]byte(call.Param("media")), &media); err != nil {
+		return nil, badRequest("can't parse media JSON object")
+	}
+
+	var (
+		messages = make([]*telegram.Message, len(media))
+		groupID  string
+	)
+
+	for i, item := range media {
+		message := s.newMessage(chat, s.Me)
+		if i == 0 {
+			groupID = strconv.Itoa(int(message.ID))
+		}
+
+		message.MediaGroupID = groupID
+		setMedia(message, item.Type, s.fileID(call, item.Media))
+		message.Caption = item.Caption
+		messages[i] = message
+	}
+
+	return messages, nil
+}
+
+func (s *Server) forwardMessage(call *Call) (interface{}, error) {
+	chat, err := s.resolveChat(call.Param("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	original, _, err := s.findMessage(call.Param("from_chat_id"), call.Param("message_id"))
+	if err != nil {
+		return nil, badRequest("message to forward not found")
+	}
+
+	message := s.newMessage(chat, s.Me)
+	id, date := message.ID, message.Date
+	*message = *original
+	message.ID, message.Date, message.Chat, message.From = id, date, *chat, s.Me
+	message.ForwardOrigin = &telegram.MessageOrigin{
+		Type:       telegram.UserOrigin,
+		Date:       original.Date,
+		SenderUser: &original.From,
+	}
+
+	if original.Chat.Type == telegram.Channel {
+		message.ForwardOrigin = &telegram.MessageOrigin{
+			Type:      telegram.ChannelOrigin,
+			Date:      original.Date,
+			Chat:      &original.Chat,
+			MessageID: original.ID,
+		}
+	}
+
+	return message, nil
+}
+
+func (s *Server) copyMessage(call *Call) (interface{}, error) {
+	chat, err := s.resolveChat(call.Param("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	original, _, err := s.findMessage(call.Param("from_chat_id"), call.Param("message_id"))
+	if err != nil {
+		return nil, badRequest("message to copy not found")
+	}
+
+	message := s.newMessage(chat, s.Me)
+	id, date := message.ID, message.Date
+	*message = *original
+	message.ID, message.Date, message.Chat, message.From = id, date, *chat, s.Me
+	if caption := call.Param("caption"); caption != "" {
+		message.Caption = caption
+	}
+
+	return map[string]interface{}{"message_id": message.ID}, nil
+}
+
+func (s *Server) editMessage(call *Call) (interface{}, error) {
+	if call.Param("inline_message_id") != "" {
+		return true, nil
+	}
+
+	message, _, err := s.findMessage(call.Param("chat_id"), call.Param("message_id"))
+	if err != nil {
+		return nil, badRequest("message to edit not found")
+	}
+
+	edited := *message
+	switch call.Method {
+	case "editMessageText":
+		edited.Text = call.Param("text")
+	case "editMessageCaption":
+		edited.Caption = call.Param("caption")
+	case "editMessageMedia":
+		var media struct {
+			Type    string `json:"type"`
+			Media   string `json:"media"`
+			Caption string `json:"caption"`
+		}
+
+		if err := json.Unmarshal([]byte(call.Param("media")), &media); err != nil {
+			return nil, badRequest("can't parse InputMedia JSON object")
+		}
+
+		setMedia(&edited, media.Type, s.fileID(call, media.Media))
+		edited.Caption = media.Caption
+	}
+
+	setReplyMarkup(&edited, call)
+	if reflect.DeepEqual(edited, *message) {
+		return nil, badRequest("message is not modified: specified new message content and reply markup are exactly the same as a current content and reply markup of the message")
+	}
+
+	edited.EditDate = int(time.Now().Unix())
+	*message = edited
+	return message, nil
+}
+
+func (s *Server) deleteMessage(call *Call) (interface{}, error) {
+	message, i, err := s.findMessage(call.Param("chat_id"), call.Param("message_id"))
+	if err != nil {
+		return nil, badRequest("message to delete not found")
+	}
+
+	chatID := message.Chat.ID
+	s.messages[chatID] = append(s.messages[chatID][:i], s.messages[chatID][i+1:]...)
+	return true, nil
+}
+
+func (s *Server) stopPoll(call *Call) (interface{}, error) {
+	message, _, err := s.findMessage(call.Param("chat_id"), call.Param("message_id"))
+	if err != nil || message.Poll == nil {
+		return nil, badRequest("message with poll to stop not found")
+	}
+
+	if message.Poll.IsClosed {
+		return nil, badRequest("poll has already been closed")
+	}
+
+	message.Poll.IsClosed = true
+	setReplyMarkup(message, call)
+	return message.Poll, nil
+}
+
+func (s *Server) getChat(call *Call) (interface{}, error) {
+	return s.resolveChat(call.Param("chat_id"))
+}
+
+func (s *Server) getChatMember(call *Call) (interface{}, error) {
+	if _, err := s.resolveChat(call.Param("chat_id")); err != nil {
+		return nil, err
+	}
+
+	userID, _ := telegram.ParseID(call.Param("user_id"))
+	if userID == s.Me.ID {
+		return telegram.ChatMember{User: s.Me, Status: telegram.ChatMemberAdministrator}, nil
+	}
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, badRequest("user not found")
+	}
+
+	return telegram.ChatMember{User: user, Status: telegram.ChatMemberMember}, nil
+}
+
+func (s *Server) getChatAdministrators(call *Call) (interface{}, error) {
+	if _, err := s.resolveChat(call.Param("chat_id")); err != nil {
+		return nil, err
+	}
+
+	return []telegram.ChatMember{}, nil
+}
+
+func (s *Server) getChatMemberCount(call *Call) (interface{}, error) {
+	if _, err := s.resolveChat(call.Param("chat_id")); err != nil {
+		return nil, err
+	}
+
+	return 2, nil
+}
+
+func (s *Server) getFile(call *Call) (interface{}, error) {
+	id := call.Param("file_id")
+	data, ok := s.files[id]
+	if !ok {
+		return nil, badRequest("invalid file_id")
+	}
+
+	return telegram.File{
+		ID:       id,
+		UniqueID: id,
+		Size:     int64(len(data)),
+		Path:     filePathPrefix + id,
+	}, nil
+}
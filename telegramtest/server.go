@@ -0,0 +1,323 @@
+// Package telegramtest provides an in-process fake Bot API server for testing bots.
+package telegramtest
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+)
+
+// DefaultToken is the bot token accepted by Server by default.
+const DefaultToken = "123456:TEST-TOKEN"
+
+// Call is a recorded Bot API call.
+type Call struct {
+	// Method is the Bot API method name.
+	Method string
+	// Params contains request parameters.
+	// Non-string JSON values are stored as raw JSON.
+	Params map[string]string
+	// Files contains uploaded files by multipart field name.
+	Files map[string][]byte
+}
+
+// Param returns the request parameter value.
+func (c Call) Param(key string) string {
+	return c.Params[key]
+}
+
+// Handler handles a Bot API method call.
+// The returned value is encoded as response result.
+// Returning *Error results in an error response.
+// Handlers are called without holding the server lock, so they may call other Server methods
+// (for example, Calls, Messages or AddUpdate).
+type Handler func(call *Call) (interface{}, error)
+
+// Server is a fake Bot API server.
+// It keeps chats, users and messages in memory, queues updates for getUpdates and records all calls.
+// Methods which are not implemented explicitly just return true.
+type Server struct {
+	*httptest.Server
+	// Token is the bot token accepted by the server.
+	Token string
+	// Me is the bot user returned by getMe.
+	Me telegram.User
+
+	users         map[telegram.ID]telegram.User
+	chats         map[telegram.ID]*telegram.Chat
+	messages      map[telegram.ID][]*telegram.Message
+	lastMessageID telegram.ID
+	files         map[string][]byte
+	updates       []telegram.Update
+	lastUpdateID  telegram.ID
+	updated       chan struct{}
+	closed        chan struct{}
+	calls         []Call
+	handlers      map[string]Handler
+	faults        []*fault
+	blocked       map[telegram.ID]bool
+	migrated      map[telegram.ID]telegram.ID
+	mu            sync.Mutex
+}
+
+// NewServer starts a new fake Bot API server.
+// Server.Close should be called when finished.
+func NewServer() *Server {
+	username := telegram.Username("test_bot")
+	s := &Server{
+		Token: DefaultToken,
+		Me: telegram.User{
+			ID:        123456,
+			IsBot:     true,
+			FirstName: "Test Bot",
+			Username:  &username,
+		},
+		users:    make(map[telegram.ID]telegram.User),
+		chats:    make(map[telegram.ID]*telegram.Chat),
+		messages: make(map[telegram.ID][]*telegram.Message),
+		files:    make(map[string][]byte),
+		updated:  make(chan struct{}),
+		closed:   make(chan struct{}),
+		handlers: make(map[string]Handler),
+		blocked:  make(map[telegram.ID]bool),
+		migrated: make(map[telegram.ID]telegram.ID),
+	}
+
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Close releases pending getUpdates requests and shuts down the server.
+func (s *Server) Close() {
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
+
+	s.mu.Unlock()
+	s.Server.Close()
+}
+
+// NewBot creates a telegram.Bot which works with this server.
+// This is the same as calling telegram.NewBot with the server client and URL as endpoint.
+func (s *Server) NewBot(clock syncf.Clock, options ...telegram.BotOption) *telegram.Bot {
+	options = append([]telegram.BotOption{telegram.WithEndpoint(s.URL)}, options...)
+	return telegram.NewBot(clock, s.Client(), s.Token, options...)
+}
+
+// Handle overrides the handler for the method.
+func (s *Server) Handle(method string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// Calls returns recorded calls of the specified methods (or all calls if no methods are specified).
+func (s *Server) Calls(methods ...string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make([]Call, 0, len(s.calls))
+	for _, call := range s.calls {
+		if len(methods) == 0 || contains(methods, call.Method) {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	file := strings.HasPrefix(path, "file/")
+	path = strings.TrimPrefix(path, "file/")
+	if !strings.HasPrefix(path, "bot"+s.Token+"/") {
+		writeError(w, &Error{Code: http.StatusUnauthorized, Description: "Unauthorized"})
+		return
+	}
+
+	path = strings.TrimPrefix(path, "bot"+s.Token+"/")
+	path = strings.TrimPrefix(path, "test/")
+	if file {
+		s.serveFile(w, path)
+		return
+	}
+
+	call, err := parseCall(path, r)
+	if err != nil {
+		writeError(w, &Error{Code: http.StatusBadRequest, Description: "Bad Request: " + err.Error()})
+		return
+	}
+
+	var result interface{}
+	if path == "getUpdates" {
+		s.record(call)
+		result, err = s.getUpdates(r, call)
+	} else {
+		result, err = s.execute(call)
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "result": result})
+}
+
+func (s *Server) record(call *Call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, *call)
+}
+
+func (s *Server) execute(call *Call) (interface{}, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, *call)
+	if err := s.checkFaults(call); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	if handler, ok := s.handlers[call.Method]; ok {
+		s.mu.Unlock()
+		return handler(call)
+	}
+
+	defer s.mu.Unlock()
+
+	if handler, ok := methods[call.Method]; ok {
+		return handler(s, call)
+	}
+
+	if strings.HasPrefix(call.Method, "send") {
+		return s.sendMessage(call)
+	}
+
+	return true, nil
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, path string) {
+	s.mu.Lock()
+	data, ok := s.files[strings.TrimPrefix(path, filePathPrefix)]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	_, _ = w.Write(data)
+}
+
+func parseCall(method string, r *http.Request) (*Call, error) {
+	call := &Call{
+		Method: method,
+		Params: make(map[string]string),
+		Files:  make(map[string][]byte),
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case "application/json":
+		raw := make(map[string]json.RawMessage)
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		for key, value := range raw {
+			var str string
+			if err := json.Unmarshal(value, &str); err == nil {
+				call.Params[key] = str
+			} else {
+				call.Params[key] = string(value)
+			}
+		}
+
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return nil, err
+		}
+
+		for key, values := range r.MultipartForm.Value {
+			call.Params[key] = values[0]
+		}
+
+		for key, headers := range r.MultipartForm.File {
+			file, err := headers[0].Open()
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := io.ReadAll(file)
+			_ = file.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			call.Files[key] = data
+		}
+
+	default:
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+
+		for key, values := range r.Form {
+			call.Params[key] = values[0]
+		}
+	}
+
+	return call, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*Error)
+	if !ok {
+		apiErr = &Error{Code: http.StatusInternalServerError, Description: err.Error()}
+	}
+
+	resp := map[string]interface{}{
+		"ok":          false,
+		"error_code":  apiErr.Code,
+		"description": apiErr.Description,
+	}
+
+	parameters := make(map[string]interface{})
+	if apiErr.RetryAfter > 0 {
+		parameters["retry_after"] = apiErr.RetryAfter
+	}
+
+	if apiErr.MigrateToChatID != 0 {
+		parameters["migrate_to_chat_id"] = apiErr.MigrateToChatID
+	}
+
+	if len(parameters) > 0 {
+		resp["parameters"] = parameters
+	}
+
+	writeJSON(w, apiErr.Code, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package telegramtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Send(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	server, bot, chat := f.Server, f.Bot, f.Chat
+
+	ctx := context.Background()
+	me, err := bot.GetMe(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, server.Me.ID, me.ID)
+
+	message, err := bot.Send(ctx, chat.ID, telegram.Text{Text: "hello"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", message.Text)
+	assert.Equal(t, telegram.PrivateChat, message.Chat.Type)
+
+	edited, err := bot.EditMessageText(ctx, message.Ref(), telegram.Text{Text: "hello"}, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, edited)
+
+	assert.Nil(t, bot.DeleteMessage(ctx, message.Ref()))
+	assert.Empty(t, server.Messages(chat.ID))
+
+	calls := server.Calls("sendMessage")
+	if assert.Len(t, calls, 1) {
+		assert.Equal(t, "1", calls[0].Param("chat_id"))
+		assert.Equal(t, "hello", calls[0].Param("text"))
+	}
+}
+
+func TestServer_HandlerCallsServer(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	server, bot, chat := f.Server, f.Bot, f.Chat
+
+	// The handler calls back into the server, which must not deadlock.
+	var calls []telegramtest.Call
+	server.Handle("getChat", func(call *telegramtest.Call) (interface{}, error) {
+		calls = server.Calls("getChat")
+		return chat, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := bot.GetChat(ctx, chat.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, chat.ID, result.ID)
+	assert.Len(t, calls, 1)
+}
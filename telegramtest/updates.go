@@ -0,0 +1,156 @@
+package telegramtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+)
+
+// AddUpdate queues the update for getUpdates.
+// The update ID is assigned automatically.
+func (s *Server) AddUpdate(update telegram.Update) telegram.Update {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addUpdate(update)
+}
+
+func (s *Server) addUpdate(update telegram.Update) telegram.Update {
+	s.lastUpdateID++
+	update.ID = s.lastUpdateID
+	s.updates = append(s.updates, update)
+	close(s.updated)
+	s.updated = make(chan struct{})
+	return update
+}
+
+// SendMessage emulates the user sending a text message to the chat.
+// If the text starts with a slash, the first word is marked as a bot_command entity.
+// The user and the chat must be registered with AddUser or AddChat.
+func (s *Server) SendMessage(userID, chatID telegram.ID, text string) *telegram.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chat, ok := s.chats[chatID]
+	if !ok {
+		panic("telegramtest: unknown chat " + chatID.String())
+	}
+
+	message := s.newMessage(chat, s.users[userID])
+	message.Text = text
+	if strings.HasPrefix(text, "/") {
+		command := strings.Fields(text)[0]
+		message.Entities = []telegram.MessageEntity{{
+			Type:   telegram.BotCommandEntity,
+			Length: len(utf16.Encode([]rune(command))),
+		}}
+	}
+
+	copied := *message
+	s.addUpdate(telegram.Update{Message: &copied})
+	return &copied
+}
+
+// PressButton emulates the user pressing the inline keyboard button with callback data.
+// Returns the callback query ID.
+func (s *Server) PressButton(userID telegram.ID, ref telegram.MessageRef, data string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	message, _, err := s.findMessage(ref.ChatID.String(), ref.ID.String())
+	if err != nil {
+		panic("telegramtest: " + err.Error())
+	}
+
+	copied := *message
+	query := &telegram.CallbackQuery{
+		ID:      strconv.Itoa(int(s.lastUpdateID + 1)),
+		From:    s.users[userID],
+		Message: &copied,
+		Data:    &data,
+	}
+
+	s.addUpdate(telegram.Update{CallbackQuery: query})
+	return query.ID
+}
+
+func (s *Server) getUpdates(r *http.Request, call *Call) (interface{}, error) {
+	var (
+		offset, _  = telegram.ParseID(call.Param("offset"))
+		limit, _   = strconv.Atoi(call.Param("limit"))
+		timeout, _ = strconv.Atoi(call.Param("timeout"))
+		allowed    []string
+		deadline   = time.After(time.Duration(timeout) * time.Second)
+	)
+
+	if value := call.Param("allowed_updates"); value != "" {
+		_ = json.Unmarshal([]byte(value), &allowed)
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	for {
+		s.mu.Lock()
+		if offset != 0 {
+			confirmed := 0
+			for confirmed < len(s.updates) && s.updates[confirmed].ID < offset {
+				confirmed++
+			}
+
+			s.updates = s.updates[confirmed:]
+		}
+
+		updates := make([]telegram.Update, 0)
+		for _, update := range s.updates {
+			if len(updates) >= limit {
+				break
+			}
+
+			if len(allowed) == 0 || contains(allowed, updateType(update)) {
+				updates = append(updates, update)
+			}
+		}
+
+		updated := s.updated
+		s.mu.Unlock()
+
+		if len(updates) > 0 || timeout <= 0 {
+			return updates, nil
+		}
+
+		select {
+		case <-updated:
+		case <-deadline:
+			return updates, nil
+		case <-s.closed:
+			return updates, nil
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		}
+	}
+}
+
+// updateType returns the update type as used in allowed_updates.
+func updateType(update telegram.Update) string {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return ""
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ""
+	}
+
+	for key, value := range fields {
+		if key != "update_id" && string(value) != "null" {
+			return key
+		}
+	}
+
+	return ""
+}
@@ -1,8 +1,6 @@
 package telegram
 
 import (
-	"time"
-
 	"github.com/jfk9w-go/flu"
 	"github.com/jfk9w-go/flu/httpf"
 )
@@ -15,6 +13,8 @@ const (
 	None ParseMode = ""
 	// Markdown is "Markdown" parse_mode value.
 	Markdown ParseMode = "Markdown"
+	// MarkdownV2 is "MarkdownV2" parse_mode value.
+	MarkdownV2 ParseMode = "MarkdownV2"
 	// HTML is "HTML" parse_mode value.
 	HTML ParseMode = "HTML"
 
@@ -34,8 +34,42 @@ const (
 	Channel     ChatType = "channel"
 )
 
-func (t ChatType) SendDelay() time.Duration {
-	return SendDelays[t]
+// Update types which can be used in allowed_updates.
+// Note that chat_member updates are not sent unless explicitly requested.
+const (
+	UpdateMessage            = "message"
+	UpdateEditedMessage      = "edited_message"
+	UpdateChannelPost        = "channel_post"
+	UpdateEditedChannelPost  = "edited_channel_post"
+	UpdateInlineQuery        = "inline_query"
+	UpdateChosenInlineResult = "chosen_inline_result"
+	UpdateCallbackQuery      = "callback_query"
+	UpdateShippingQuery      = "shipping_query"
+	UpdatePreCheckoutQuery   = "pre_checkout_query"
+	UpdatePoll               = "poll"
+	UpdatePollAnswer         = "poll_answer"
+	UpdateMyChatMember       = "my_chat_member"
+	UpdateChatMember         = "chat_member"
+	UpdateChatJoinRequest    = "chat_join_request"
+)
+
+// AllUpdates contains all supported update types.
+// It may be used in GetUpdatesOptions.AllowedUpdates to receive chat_member updates along with the others.
+var AllUpdates = []string{
+	UpdateMessage,
+	UpdateEditedMessage,
+	UpdateChannelPost,
+	UpdateEditedChannelPost,
+	UpdateInlineQuery,
+	UpdateChosenInlineResult,
+	UpdateCallbackQuery,
+	UpdateShippingQuery,
+	UpdatePreCheckoutQuery,
+	UpdatePoll,
+	UpdatePollAnswer,
+	UpdateMyChatMember,
+	UpdateChatMember,
+	UpdateChatJoinRequest,
 }
 
 type BotCommandScopeType string
@@ -62,32 +96,33 @@ type (
 
 	// Chat (https://core.telegram.org/bots/api#chat)
 	Chat struct {
-		ID                          ID        `json:"id"`
-		Type                        ChatType  `json:"type"`
-		Title                       string    `json:"title"`
-		Username                    *Username `json:"username"`
-		FirstName                   string    `json:"first_name"`
-		LastName                    string    `json:"last_name"`
-		AllMembersAreAdministrators bool      `json:"all_members_are_administrators"`
-		InviteLink                  string    `json:"invite_link"`
-	}
-
-	MessageFile struct {
-		ID string `json:"file_id"`
-	}
-
-	// Message (https://core.telegram.org/bots/api#message)
-	Message struct {
-		ID             ID              `json:"message_id"`
-		From           User            `json:"from"`
-		Date           int             `json:"date"`
-		Chat           Chat            `json:"chat"`
-		Text           string          `json:"text"`
-		Entities       []MessageEntity `json:"entities"`
-		ReplyToMessage *Message        `json:"reply_to_message"`
-		Photo          []MessageFile   `json:"photo"`
-		Video          *MessageFile    `json:"video"`
-		Animation      *MessageFile    `json:"animation"`
+		ID                          ID               `json:"id"`
+		Type                        ChatType         `json:"type"`
+		Title                       string           `json:"title"`
+		Username                    *Username        `json:"username"`
+		FirstName                   string           `json:"first_name"`
+		LastName                    string           `json:"last_name"`
+		AllMembersAreAdministrators bool             `json:"all_members_are_administrators"`
+		InviteLink                  string           `json:"invite_link"`
+		Permissions                 *ChatPermissions `json:"permissions"`
+	}
+
+	// Location (https://core.telegram.org/bots/api#location)
+	Location struct {
+		Longitude            float64 `json:"longitude"`
+		Latitude             float64 `json:"latitude"`
+		HorizontalAccuracy   float64 `json:"horizontal_accuracy"`
+		LivePeriod           int     `json:"live_period"`
+		Heading              int     `json:"heading"`
+		ProximityAlertRadius int     `json:"proximity_alert_radius"`
+	}
+
+	// File (https://core.telegram.org/bots/api#file)
+	File struct {
+		ID       string `json:"file_id"`
+		UniqueID string `json:"file_unique_id"`
+		Size     int64  `json:"file_size"`
+		Path     string `json:"file_path"`
 	}
 
 	// MessageRef is used for message copying and forwarding.
@@ -96,29 +131,65 @@ type (
 		ID     ID     `url:"message_id"`
 	}
 
-	// MessageEntity (https://core.telegram.org/bots/api#messageentity)
-	MessageEntity struct {
-		Type   string `json:"type"`
-		Offset int    `json:"offset"`
-		Length int    `json:"length"`
-		URL    string `json:"url"`
-		User   *User  `json:"user"`
-	}
-
 	// Update (https://core.telegram.org/bots/api#update)
 	Update struct {
-		ID                ID             `json:"update_id"`
-		Message           *Message       `json:"message"`
-		EditedMessage     *Message       `json:"edited_message"`
-		ChannelPost       *Message       `json:"channel_post"`
-		EditedChannelPost *Message       `json:"edited_message_post"`
-		CallbackQuery     *CallbackQuery `json:"callback_query"`
+		ID                 ID                  `json:"update_id"`
+		Message            *Message            `json:"message"`
+		EditedMessage      *Message            `json:"edited_message"`
+		ChannelPost        *Message            `json:"channel_post"`
+		EditedChannelPost  *Message            `json:"edited_channel_post"`
+		InlineQuery        *InlineQuery        `json:"inline_query"`
+		ChosenInlineResult *ChosenInlineResult `json:"chosen_inline_result"`
+		CallbackQuery      *CallbackQuery      `json:"callback_query"`
+		ShippingQuery      *ShippingQuery      `json:"shipping_query"`
+		PreCheckoutQuery   *PreCheckoutQuery   `json:"pre_checkout_query"`
+		Poll               *Poll               `json:"poll"`
+		PollAnswer         *PollAnswer         `json:"poll_answer"`
+		MyChatMember       *ChatMemberUpdated  `json:"my_chat_member"`
+		ChatMember         *ChatMemberUpdated  `json:"chat_member"`
+		ChatJoinRequest    *ChatJoinRequest    `json:"chat_join_request"`
 	}
 
 	// ChatMember (https://core.telegram.org/bots/api#chatmember)
+	// Fields are filled depending on Status.
 	ChatMember struct {
-		User   User   `json:"user"`
-		Status string `json:"status"`
+		User        User             `json:"user"`
+		Status      ChatMemberStatus `json:"status"`
+		CustomTitle string           `json:"custom_title"`
+		IsAnonymous bool             `json:"is_anonymous"`
+		UntilDate   int64            `json:"until_date"`
+		IsMember    bool             `json:"is_member"`
+
+		// administrator rights
+		CanBeEdited         bool `json:"can_be_edited"`
+		CanManageChat       bool `json:"can_manage_chat"`
+		CanDeleteMessages   bool `json:"can_delete_messages"`
+		CanManageVideoChats bool `json:"can_manage_video_chats"`
+		CanRestrictMembers  bool `json:"can_restrict_members"`
+		CanPromoteMembers   bool `json:"can_promote_members"`
+		CanPostStories      bool `json:"can_post_stories"`
+		CanEditStories      bool `json:"can_edit_stories"`
+		CanDeleteStories    bool `json:"can_delete_stories"`
+		CanPostMessages     bool `json:"can_post_messages"`
+		CanEditMessages     bool `json:"can_edit_messages"`
+
+		// shared by administrator rights and restricted member permissions
+		CanChangeInfo   bool `json:"can_change_info"`
+		CanInviteUsers  bool `json:"can_invite_users"`
+		CanPinMessages  bool `json:"can_pin_messages"`
+		CanManageTopics bool `json:"can_manage_topics"`
+
+		// restricted member permissions
+		CanSendMessages       bool `json:"can_send_messages"`
+		CanSendAudios         bool `json:"can_send_audios"`
+		CanSendDocuments      bool `json:"can_send_documents"`
+		CanSendPhotos         bool `json:"can_send_photos"`
+		CanSendVideos         bool `json:"can_send_videos"`
+		CanSendVideoNotes     bool `json:"can_send_video_notes"`
+		CanSendVoiceNotes     bool `json:"can_send_voice_notes"`
+		CanSendPolls          bool `json:"can_send_polls"`
+		CanSendOtherMessages  bool `json:"can_send_other_messages"`
+		CanAddWebPagePreviews bool `json:"can_add_web_page_previews"`
 	}
 
 	// CallbackQuery (https://core.telegram.org/bots/api#callbackquery)
@@ -165,6 +236,19 @@ type (
 		Command     string `json:"command"`
 		Description string `json:"description"`
 	}
+
+	// WebhookInfo (https://core.telegram.org/bots/api#webhookinfo)
+	WebhookInfo struct {
+		URL                          string   `json:"url"`
+		HasCustomCertificate         bool     `json:"has_custom_certificate"`
+		PendingUpdateCount           int      `json:"pending_update_count"`
+		IPAddress                    string   `json:"ip_address"`
+		LastErrorDate                int      `json:"last_error_date"`
+		LastErrorMessage             string   `json:"last_error_message"`
+		LastSynchronizationErrorDate int      `json:"last_synchronization_error_date"`
+		MaxConnections               int      `json:"max_connections"`
+		AllowedUpdates               []string `json:"allowed_updates"`
+	}
 )
 
 func (m *Message) Ref() MessageRef {
@@ -174,6 +258,42 @@ func (m *Message) Ref() MessageRef {
 	}
 }
 
+// kind returns the update type as used in allowed_updates.
+func (u Update) kind() string {
+	switch {
+	case u.Message != nil:
+		return UpdateMessage
+	case u.EditedMessage != nil:
+		return UpdateEditedMessage
+	case u.ChannelPost != nil:
+		return UpdateChannelPost
+	case u.EditedChannelPost != nil:
+		return UpdateEditedChannelPost
+	case u.InlineQuery != nil:
+		return UpdateInlineQuery
+	case u.ChosenInlineResult != nil:
+		return UpdateChosenInlineResult
+	case u.CallbackQuery != nil:
+		return UpdateCallbackQuery
+	case u.ShippingQuery != nil:
+		return UpdateShippingQuery
+	case u.PreCheckoutQuery != nil:
+		return UpdatePreCheckoutQuery
+	case u.Poll != nil:
+		return UpdatePoll
+	case u.PollAnswer != nil:
+		return UpdatePollAnswer
+	case u.MyChatMember != nil:
+		return UpdateMyChatMember
+	case u.ChatMember != nil:
+		return UpdateChatMember
+	case u.ChatJoinRequest != nil:
+		return UpdateChatJoinRequest
+	default:
+		return ""
+	}
+}
+
 func (r MessageRef) kind() string {
 	return "__internal__"
 }
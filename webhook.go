@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/jfk9w-go/flu"
+	"github.com/jfk9w-go/flu/logf"
+	"github.com/jfk9w-go/flu/syncf"
+)
+
+// SecretTokenHeader is the header containing webhook secret token set with SetWebhook.
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type webhook struct {
+	bot         *Bot
+	secretToken string
+	updates     chan Update
+}
+
+func (h *webhook) String() string {
+	return rootLoggerName + ".webhook"
+}
+
+func (h *webhook) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.secretToken != "" &&
+		subtle.ConstantTimeCompare([]byte(req.Header.Get(SecretTokenHeader)), []byte(h.secretToken)) != 1 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	ctx := req.Context()
+	var update Update
+	if err := flu.JSON(&update).DecodeFrom(req.Body); err != nil {
+		logf.Get(h).Warnf(ctx, "decode update: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	logf.Get(h).Tracef(ctx, "received update %d", update.ID)
+	if err := h.bot.receive(ctx, update, h.updates); err != nil {
+		// Telegram will redeliver the update later.
+		if !syncf.IsContextRelated(err) {
+			logf.Get(h).Warnf(ctx, "receive update %d: %v", update.ID, err)
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,91 @@
+package telegram_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jfk9w-go/flu/syncf"
+	telegram "github.com/jfk9w-go/telegram-bot-api"
+	"github.com/jfk9w-go/telegram-bot-api/telegramtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBot_Webhook(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	handler := f.Bot.Webhook("secret")
+	commands := make(chan string, 1)
+	f.Bot.CommandListenerFunc(func(ctx context.Context, client telegram.Client, cmd *telegram.Command) error {
+		commands <- cmd.Key
+		return nil
+	})
+
+	update := `{"update_id": 1, "message": {"message_id": 1, "date": 1,
+		"chat": {"id": 1, "type": "private"}, "from": {"id": 1, "first_name": "User"},
+		"text": "/start", "entities": [{"type": "bot_command", "offset": 0, "length": 6}]}}`
+
+	serve := func(method, secret, body string) int {
+		req := httptest.NewRequest(method, "/webhook", strings.NewReader(body)).WithContext(timeout(t))
+		if secret != "" {
+			req.Header.Set(telegram.SecretTokenHeader, secret)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		secret string
+		body   string
+		code   int
+	}{
+		{"get", http.MethodGet, "secret", update, http.StatusMethodNotAllowed},
+		{"missing secret", http.MethodPost, "", update, http.StatusUnauthorized},
+		{"wrong secret", http.MethodPost, "wrong", update, http.StatusUnauthorized},
+		{"bad json", http.MethodPost, "secret", `{"update_id": `, http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.code, serve(tc.method, tc.secret, tc.body))
+		})
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "secret", update))
+	assert.Equal(t, "/start", receive(t, commands, "command"))
+
+	// Updates are not polled when webhook is used.
+	assert.Empty(t, f.Server.Calls("getUpdates"))
+}
+
+func TestBot_SetWebhook(t *testing.T) {
+	f := telegramtest.Setup(t, syncf.DefaultClock)
+	ctx := timeout(t)
+	assert.NoError(t, f.Bot.SetWebhook(ctx, "https://example.com/webhook", &telegram.WebhookOptions{
+		MaxConnections:     10,
+		AllowedUpdates:     []string{telegram.UpdateMessage, telegram.UpdateCallbackQuery},
+		DropPendingUpdates: true,
+		SecretToken:        "secret",
+	}))
+
+	assert.NoError(t, f.Bot.SetWebhook(ctx, "https://example.com/webhook", nil))
+	assert.NoError(t, f.Bot.DeleteWebhook(ctx, true))
+	assert.NoError(t, f.Bot.DeleteWebhook(ctx, false))
+
+	calls := f.Server.Calls("setWebhook", "deleteWebhook")
+	if assert.Len(t, calls, 4) {
+		assert.Equal(t, map[string]string{
+			"url":                  "https://example.com/webhook",
+			"max_connections":      "10",
+			"allowed_updates":      `["message","callback_query"]`,
+			"drop_pending_updates": "true",
+			"secret_token":         "secret",
+		}, calls[0].Params)
+		assert.Equal(t, map[string]string{"url": "https://example.com/webhook"}, calls[1].Params)
+		assert.Equal(t, map[string]string{"drop_pending_updates": "1"}, calls[2].Params)
+		assert.Empty(t, calls[3].Params)
+	}
+}